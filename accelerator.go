@@ -0,0 +1,271 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+/*
+accelerator.go holds the Supervisor's registry of keyboard shortcuts, such as
+the "Ctrl-S" accelerators shown on menu items.
+*/
+
+// Error messages for keyboard shortcuts.
+var (
+	ErrShortcutConflict = errors.New("keyboard shortcut is already registered")
+)
+
+// Accelerator is a parsed keyboard shortcut, like "Ctrl-S" or "Ctrl-Shift-Z".
+type Accelerator struct {
+	Key   string // ui key name, e.g. "s", "F1", "Enter"
+	Shift bool
+	Ctrl  bool
+	Alt   bool
+}
+
+// acceleratorKeys maps alternative spellings of keys in accelerator strings
+// to the ui package's key names.
+var acceleratorKeys = map[string]string{
+	"esc":       KeyEscape,
+	"escape":    KeyEscape,
+	"enter":     KeyEnter,
+	"return":    KeyEnter,
+	"tab":       KeyTab,
+	"space":     KeySpace,
+	"backspace": KeyBackspace,
+	"del":       KeyDelete,
+	"delete":    KeyDelete,
+	"up":        KeyArrowUp,
+	"down":      KeyArrowDown,
+	"left":      KeyArrowLeft,
+	"right":     KeyArrowRight,
+	"home":      KeyHome,
+	"end":       KeyEnd,
+	"pgup":      KeyPageUp,
+	"pageup":    KeyPageUp,
+	"pgdn":      KeyPageDown,
+	"pagedown":  KeyPageDown,
+}
+
+// ParseAccelerator parses a shortcut string like "Ctrl-S", "Ctrl+Shift+Z" or
+// "Alt-F4". Modifiers (Ctrl, Shift, Alt) may come in any order, separated by
+// dashes or plus signs, and the final part names the key.
+func ParseAccelerator(accel string) (Accelerator, error) {
+	var (
+		result Accelerator
+		parts  = splitAccelerator(accel)
+	)
+
+	if len(parts) == 0 {
+		return result, fmt.Errorf("ParseAccelerator(%q): empty shortcut", accel)
+	}
+
+	for _, part := range parts[:len(parts)-1] {
+		switch strings.ToLower(part) {
+		case "ctrl", "control", "cmd":
+			result.Ctrl = true
+		case "shift":
+			result.Shift = true
+		case "alt", "option":
+			result.Alt = true
+		default:
+			return result, fmt.Errorf("ParseAccelerator(%q): unknown modifier %q", accel, part)
+		}
+	}
+
+	key := parts[len(parts)-1]
+	if name, ok := acceleratorKeys[strings.ToLower(key)]; ok {
+		result.Key = name
+	} else {
+		result.Key = normalizeKey(key)
+	}
+
+	return result, nil
+}
+
+// splitAccelerator splits the parts of a shortcut string on dashes and plus
+// signs, allowing the final key itself to be a dash or plus ("Ctrl--").
+func splitAccelerator(accel string) []string {
+	var (
+		parts = []string{}
+		start = 0
+	)
+	accel = strings.TrimSpace(accel)
+	for i, r := range accel {
+		if (r == '-' || r == '+') && i > start {
+			parts = append(parts, accel[start:i])
+			start = i + 1
+		}
+	}
+	if start < len(accel) {
+		parts = append(parts, accel[start:])
+	}
+	return parts
+}
+
+// String returns the canonical form of the accelerator, e.g. "Ctrl-Shift-Z".
+func (a Accelerator) String() string {
+	var parts = []string{}
+	if a.Ctrl {
+		parts = append(parts, "Ctrl")
+	}
+	if a.Alt {
+		parts = append(parts, "Alt")
+	}
+	if a.Shift {
+		parts = append(parts, "Shift")
+	}
+	if a.Key != "" {
+		first, size := utf8.DecodeRuneInString(a.Key)
+		parts = append(parts, string(unicode.ToUpper(first))+a.Key[size:])
+	}
+	return strings.Join(parts, "-")
+}
+
+// Matches returns whether the accelerator matches a key event's data.
+func (a Accelerator) Matches(ed EventData) bool {
+	return a.Key == ed.Key && a.Shift == ed.Shift && a.Ctrl == ed.Ctrl && a.Alt == ed.Alt
+}
+
+// Shortcut is a keyboard shortcut registered with the Supervisor.
+type Shortcut struct {
+	Accelerator Accelerator
+	Command     func()
+
+	// Scope limits the shortcut to only work while the given Window has
+	// focus. A nil Scope means the shortcut works everywhere.
+	Scope *Window
+
+	// owner is the widget that registered the shortcut (e.g. the MenuButton
+	// of a MenuItem); the shortcut is inactive while it's hidden, and is
+	// scoped to the managed Window that the owner belongs to, if any.
+	owner Widget
+}
+
+// String returns the accelerator of the shortcut.
+func (sc *Shortcut) String() string {
+	return sc.Accelerator.String()
+}
+
+// window returns the Window that the shortcut is scoped to, if any.
+func (sc *Shortcut) window() *Window {
+	if sc.Scope != nil {
+		return sc.Scope
+	}
+	if sc.owner != nil {
+		var node = sc.owner
+		for node != nil {
+			if window, ok := node.(*Window); ok && window.managed {
+				return window
+			}
+			node, _ = node.Parent()
+		}
+	}
+	return nil
+}
+
+// active returns whether the shortcut can fire right now.
+func (sc *Shortcut) active() bool {
+	if sc.owner != nil && sc.owner.Hidden() {
+		return false
+	}
+	if window := sc.window(); window != nil {
+		return window.Focused() && !window.Hidden()
+	}
+	return true
+}
+
+// AddShortcut registers a keyboard shortcut with the Supervisor. The command
+// function is called when the keys are pressed.
+//
+// The scope is optional: if not nil, the shortcut only works while that
+// Window has focus. A scoped shortcut takes priority over a global shortcut
+// with the same keys. Registering the same keys twice in the same scope
+// returns ErrShortcutConflict.
+func (s *Supervisor) AddShortcut(accel string, scope *Window, command func()) (*Shortcut, error) {
+	return s.addShortcut(accel, scope, nil, command)
+}
+
+// addShortcut registers a shortcut, optionally on behalf of an owner widget.
+func (s *Supervisor) addShortcut(accel string, scope *Window, owner Widget, command func()) (*Shortcut, error) {
+	parsed, err := ParseAccelerator(accel)
+	if err != nil {
+		return nil, err
+	}
+
+	sc := &Shortcut{
+		Accelerator: parsed,
+		Command:     command,
+		Scope:       scope,
+		owner:       owner,
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, other := range s.shortcuts {
+		if other.Accelerator == parsed && other.window() == sc.window() {
+			return nil, fmt.Errorf("%w: %s", ErrShortcutConflict, parsed)
+		}
+	}
+
+	s.shortcuts = append(s.shortcuts, sc)
+	return sc, nil
+}
+
+// RemoveShortcut unregisters a keyboard shortcut. Returns false if it was
+// not registered.
+func (s *Supervisor) RemoveShortcut(sc *Shortcut) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for i, other := range s.shortcuts {
+		if other == sc {
+			s.shortcuts = append(s.shortcuts[:i], s.shortcuts[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Shortcuts returns all of the registered keyboard shortcuts.
+func (s *Supervisor) Shortcuts() []*Shortcut {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return append([]*Shortcut{}, s.shortcuts...)
+}
+
+// runShortcut is called by loopKeys when a key is first pressed, and runs the
+// matching shortcut's command. Shortcuts scoped to the focused Window are
+// checked before global ones. Shortcuts do not fire while a modal is active.
+//
+// Returns true if a shortcut was run.
+func (s *Supervisor) runShortcut(ed EventData) bool {
+	if s.GetModal() != nil {
+		return false
+	}
+
+	var match *Shortcut
+	for _, sc := range s.Shortcuts() {
+		if !sc.Accelerator.Matches(ed) || !sc.active() {
+			continue
+		}
+
+		// A window scoped shortcut wins; otherwise keep the first global one.
+		if sc.window() != nil {
+			match = sc
+			break
+		} else if match == nil {
+			match = sc
+		}
+	}
+
+	if match != nil && match.Command != nil {
+		match.Command()
+		return true
+	}
+	return false
+}
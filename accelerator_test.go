@@ -0,0 +1,83 @@
+package ui
+
+import (
+	"errors"
+	"testing"
+
+	"git.kirsle.net/go/render/event"
+)
+
+func TestParseAccelerator(t *testing.T) {
+	var tests = []struct {
+		In     string
+		Expect Accelerator
+		String string
+	}{
+		{"Ctrl-S", Accelerator{Key: "s", Ctrl: true}, "Ctrl-S"},
+		{"Ctrl+Shift+Z", Accelerator{Key: "z", Ctrl: true, Shift: true}, "Ctrl-Shift-Z"},
+		{"Alt-F4", Accelerator{Key: "F4", Alt: true}, "Alt-F4"},
+		{"F1", Accelerator{Key: "F1"}, "F1"},
+		{"Ctrl--", Accelerator{Key: "-", Ctrl: true}, "Ctrl--"},
+		{"Shift-Esc", Accelerator{Key: KeyEscape, Shift: true}, "Shift-Escape"},
+		{"Alt-é", Accelerator{Key: "é", Alt: true}, "Alt-É"},
+	}
+
+	for _, test := range tests {
+		actual, err := ParseAccelerator(test.In)
+		if err != nil {
+			t.Errorf("ParseAccelerator(%q): %s", test.In, err)
+			continue
+		}
+		if actual != test.Expect {
+			t.Errorf("ParseAccelerator(%q): expected %+v, got %+v", test.In, test.Expect, actual)
+		}
+		if actual.String() != test.String {
+			t.Errorf("ParseAccelerator(%q).String(): expected %q, got %q", test.In, test.String, actual.String())
+		}
+	}
+
+	if _, err := ParseAccelerator("Super-S"); err == nil {
+		t.Errorf("ParseAccelerator(Super-S): expected an error")
+	}
+}
+
+func TestShortcuts(t *testing.T) {
+	var (
+		s     = NewSupervisor()
+		saved int
+	)
+
+	if _, err := s.AddShortcut("Ctrl-S", nil, func() { saved++ }); err != nil {
+		t.Fatalf("AddShortcut: %s", err)
+	}
+	if _, err := s.AddShortcut("ctrl+s", nil, func() {}); !errors.Is(err, ErrShortcutConflict) {
+		t.Errorf("expected ErrShortcutConflict, got %v", err)
+	}
+
+	// A menu item keeps its conflict for the caller.
+	menu := NewMenu("File")
+	item := menu.AddItemAccel("Save", "Ctrl-S", func() {})
+	menu.Supervise(s)
+	if err := item.ShortcutError(); !errors.Is(err, ErrShortcutConflict) {
+		t.Errorf("expected the menu item to keep ErrShortcutConflict, got %v", err)
+	}
+
+	ev := event.NewState()
+	ev.Ctrl = true
+	ev.SetKeyDown("s", true)
+	s.Loop(ev)
+	s.Loop(ev) // held down: fires only once
+	if saved != 1 {
+		t.Errorf("expected the shortcut to run once, ran %d times", saved)
+	}
+
+	// Not while a modal is active.
+	ev.SetKeyDown("s", false)
+	s.Loop(ev)
+	s.PushModal(NewFrame("Modal"))
+	ev.SetKeyDown("s", true)
+	s.Loop(ev)
+	if saved != 1 {
+		t.Errorf("expected the shortcut not to run under a modal")
+	}
+}
@@ -0,0 +1,383 @@
+package ui
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// Observable is a value that tells its subscribers when it changes.
+type Observable interface {
+	// Subscribe calls fn each time the value changes, until the returned
+	// function is called. The subscribers run on the goroutine that changed
+	// the value.
+	Subscribe(fn func()) (unsubscribe func())
+}
+
+// Binding is an Observable value that can be shown as text, such as the
+// Binding of a Label.
+type Binding interface {
+	Observable
+	String() string
+}
+
+// observers is the list of subscribers to an Observable.
+type observers struct {
+	lock sync.Mutex
+	next int
+	fns  []observer
+}
+
+type observer struct {
+	id int
+	fn func()
+}
+
+// Subscribe calls fn each time the value changes.
+func (o *observers) Subscribe(fn func()) func() {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	o.next++
+	id := o.next
+	o.fns = append(o.fns, observer{id, fn})
+
+	return func() {
+		o.lock.Lock()
+		defer o.lock.Unlock()
+		for i, other := range o.fns {
+			if other.id == id {
+				o.fns = append(o.fns[:i:i], o.fns[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// notify the subscribers, without holding the lock so they may subscribe
+// or unsubscribe.
+func (o *observers) notify() {
+	o.lock.Lock()
+	fns := append([]observer{}, o.fns...)
+	o.lock.Unlock()
+
+	for _, other := range fns {
+		other.fn()
+	}
+}
+
+// Value is an observable value of any type.
+type Value struct {
+	observers
+	lock  sync.RWMutex
+	value interface{}
+}
+
+// NewValue creates an observable value.
+func NewValue(v interface{}) *Value {
+	return &Value{value: v}
+}
+
+// Get the value.
+func (v *Value) Get() interface{} {
+	v.lock.RLock()
+	defer v.lock.RUnlock()
+	return v.value
+}
+
+// Set the value. The subscribers are notified if it changed.
+func (v *Value) Set(value interface{}) {
+	v.lock.Lock()
+	if valuesEqual(v.value, value) {
+		v.lock.Unlock()
+		return
+	}
+	v.value = value
+	v.lock.Unlock()
+	v.notify()
+}
+
+// String formats the value with fmt.Sprint.
+func (v *Value) String() string {
+	return fmt.Sprint(v.Get())
+}
+
+// valuesEqual compares two values of any type.
+func valuesEqual(a, b interface{}) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if reflect.TypeOf(a).Comparable() && reflect.TypeOf(b).Comparable() {
+		return a == b
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// StringValue is an observable string.
+type StringValue struct {
+	observers
+	lock  sync.RWMutex
+	value string
+}
+
+// NewStringValue creates an observable string.
+func NewStringValue(v string) *StringValue {
+	return &StringValue{value: v}
+}
+
+// Get the value.
+func (v *StringValue) Get() string {
+	v.lock.RLock()
+	defer v.lock.RUnlock()
+	return v.value
+}
+
+// Set the value. The subscribers are notified if it changed.
+func (v *StringValue) Set(value string) {
+	v.lock.Lock()
+	if v.value == value {
+		v.lock.Unlock()
+		return
+	}
+	v.value = value
+	v.lock.Unlock()
+	v.notify()
+}
+
+// String returns the value.
+func (v *StringValue) String() string {
+	return v.Get()
+}
+
+// IntValue is an observable int.
+type IntValue struct {
+	observers
+	lock  sync.RWMutex
+	value int
+}
+
+// NewIntValue creates an observable int.
+func NewIntValue(v int) *IntValue {
+	return &IntValue{value: v}
+}
+
+// Get the value.
+func (v *IntValue) Get() int {
+	v.lock.RLock()
+	defer v.lock.RUnlock()
+	return v.value
+}
+
+// Set the value. The subscribers are notified if it changed.
+func (v *IntValue) Set(value int) {
+	v.lock.Lock()
+	if v.value == value {
+		v.lock.Unlock()
+		return
+	}
+	v.value = value
+	v.lock.Unlock()
+	v.notify()
+}
+
+// Add to the value, e.g. to count something.
+func (v *IntValue) Add(delta int) {
+	v.lock.Lock()
+	if delta == 0 {
+		v.lock.Unlock()
+		return
+	}
+	v.value += delta
+	v.lock.Unlock()
+	v.notify()
+}
+
+// String formats the value.
+func (v *IntValue) String() string {
+	return strconv.Itoa(v.Get())
+}
+
+// FloatValue is an observable float64.
+type FloatValue struct {
+	observers
+	lock  sync.RWMutex
+	value float64
+}
+
+// NewFloatValue creates an observable float64.
+func NewFloatValue(v float64) *FloatValue {
+	return &FloatValue{value: v}
+}
+
+// Get the value.
+func (v *FloatValue) Get() float64 {
+	v.lock.RLock()
+	defer v.lock.RUnlock()
+	return v.value
+}
+
+// Set the value. The subscribers are notified if it changed.
+func (v *FloatValue) Set(value float64) {
+	v.lock.Lock()
+	if v.value == value {
+		v.lock.Unlock()
+		return
+	}
+	v.value = value
+	v.lock.Unlock()
+	v.notify()
+}
+
+// String formats the value.
+func (v *FloatValue) String() string {
+	return strconv.FormatFloat(v.Get(), 'g', -1, 64)
+}
+
+// BoolValue is an observable bool.
+type BoolValue struct {
+	observers
+	lock  sync.RWMutex
+	value bool
+}
+
+// NewBoolValue creates an observable bool.
+func NewBoolValue(v bool) *BoolValue {
+	return &BoolValue{value: v}
+}
+
+// Get the value.
+func (v *BoolValue) Get() bool {
+	v.lock.RLock()
+	defer v.lock.RUnlock()
+	return v.value
+}
+
+// Set the value. The subscribers are notified if it changed.
+func (v *BoolValue) Set(value bool) {
+	v.lock.Lock()
+	if v.value == value {
+		v.lock.Unlock()
+		return
+	}
+	v.value = value
+	v.lock.Unlock()
+	v.notify()
+}
+
+// Toggle the value.
+func (v *BoolValue) Toggle() {
+	v.lock.Lock()
+	v.value = !v.value
+	v.lock.Unlock()
+	v.notify()
+}
+
+// String formats the value.
+func (v *BoolValue) String() string {
+	return strconv.FormatBool(v.Get())
+}
+
+// Computed is a read-only value derived from other observables, such as a
+// label's text that formats two other values:
+//
+//	health := ui.NewComputed(func() interface{} {
+//		return fmt.Sprintf("%d/%d HP", hp.Get(), maxHP.Get())
+//	}, hp, maxHP)
+//	label := ui.NewLabel(ui.Label{Binding: health})
+//
+// It is recomputed when any of its dependencies change, and notifies its
+// own subscribers if the result is different.
+type Computed struct {
+	observers
+	lock        sync.RWMutex
+	fn          func() interface{}
+	value       interface{}
+	unsubscribe []func()
+}
+
+// NewComputed creates a value computed by fn from its dependencies.
+func NewComputed(fn func() interface{}, deps ...Observable) *Computed {
+	c := &Computed{
+		fn:    fn,
+		value: fn(),
+	}
+	for _, dep := range deps {
+		c.unsubscribe = append(c.unsubscribe, dep.Subscribe(c.update))
+	}
+	return c
+}
+
+// update recomputes the value.
+func (c *Computed) update() {
+	value := c.fn()
+
+	c.lock.Lock()
+	if valuesEqual(c.value, value) {
+		c.lock.Unlock()
+		return
+	}
+	c.value = value
+	c.lock.Unlock()
+	c.notify()
+}
+
+// Get the value.
+func (c *Computed) Get() interface{} {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.value
+}
+
+// String formats the value with fmt.Sprint.
+func (c *Computed) String() string {
+	return fmt.Sprint(c.Get())
+}
+
+// Close unsubscribes from the dependencies. The value stops updating.
+func (c *Computed) Close() {
+	for _, fn := range c.unsubscribe {
+		fn()
+	}
+	c.unsubscribe = nil
+}
+
+// boundValue tracks the changes of a widget's Observable, so the widget
+// only updates when the value changed.
+type boundValue struct {
+	stale       int32 // atomic: set by the subscriber
+	observable  Observable
+	unsubscribe func()
+}
+
+// changed returns whether the value changed since the last call, or is a
+// different Observable. The caller must pass a nil interface for no value.
+func (b *boundValue) changed(o Observable) bool {
+	if o != b.observable {
+		if b.unsubscribe != nil {
+			b.unsubscribe()
+			b.unsubscribe = nil
+		}
+		b.observable = o
+		atomic.StoreInt32(&b.stale, 0)
+		if o != nil {
+			b.unsubscribe = o.Subscribe(func() {
+				atomic.StoreInt32(&b.stale, 1)
+			})
+		}
+		return true
+	}
+	return atomic.SwapInt32(&b.stale, 0) == 1
+}
+
+// release unsubscribes from the Observable, so it no longer holds on to the
+// widget. The widget subscribes again if it's computed after all.
+func (b *boundValue) release() {
+	if b.unsubscribe != nil {
+		b.unsubscribe()
+		b.unsubscribe = nil
+	}
+	b.observable = nil
+	atomic.StoreInt32(&b.stale, 0)
+}
@@ -0,0 +1,172 @@
+package ui_test
+
+import (
+	"fmt"
+	"testing"
+
+	"git.kirsle.net/go/render"
+	"git.kirsle.net/go/ui"
+	"git.kirsle.net/go/ui/softrender"
+)
+
+// countingEngine counts the text measurements of a softrender engine.
+type countingEngine struct {
+	*softrender.Engine
+	measured int
+}
+
+func (e *countingEngine) ComputeTextRect(text render.Text) (render.Rect, error) {
+	e.measured++
+	return e.Engine.ComputeTextRect(text)
+}
+
+// emptyTextCache gives a test an empty DefaultTextCache, so the engine
+// measures each new line of text once. Defer the returned function.
+func emptyTextCache() func() {
+	old := ui.DefaultTextCache
+	ui.DefaultTextCache = ui.NewTextCache()
+	return func() {
+		ui.DefaultTextCache = old
+	}
+}
+
+func TestValues(t *testing.T) {
+	var (
+		v     = ui.NewIntValue(1)
+		calls int
+	)
+	unsubscribe := v.Subscribe(func() {
+		calls++
+	})
+
+	v.Set(1) // unchanged
+	v.Set(2)
+	v.Add(3)
+	if calls != 2 || v.Get() != 5 || v.String() != "5" {
+		t.Errorf("expected 2 calls and value 5, got %d calls and %d", calls, v.Get())
+	}
+
+	unsubscribe()
+	v.Set(6)
+	if calls != 2 {
+		t.Errorf("expected no calls after unsubscribing, got %d", calls)
+	}
+
+	any := ui.NewValue([]string{"a"})
+	any.Subscribe(func() {
+		calls++
+	})
+	any.Set([]string{"a"})
+	if calls != 2 {
+		t.Errorf("expected an equal slice not to notify")
+	}
+}
+
+func TestComputed(t *testing.T) {
+	var (
+		hp    = ui.NewIntValue(7)
+		maxHP = ui.NewIntValue(10)
+		calls int
+	)
+	health := ui.NewComputed(func() interface{} {
+		return fmt.Sprintf("%d/%d HP", hp.Get(), maxHP.Get())
+	}, hp, maxHP)
+	health.Subscribe(func() {
+		calls++
+	})
+
+	hp.Add(-2)
+	maxHP.Set(12)
+	if health.String() != "5/12 HP" || calls != 2 {
+		t.Errorf("expected 5/12 HP after 2 calls, got %s after %d", health, calls)
+	}
+
+	health.Close()
+	hp.Set(12)
+	if health.String() != "5/12 HP" || calls != 2 {
+		t.Errorf("expected a closed value to stop updating, got %s", health)
+	}
+}
+
+// A bound Label is only measured again when its value changes.
+func TestLabelBinding(t *testing.T) {
+	defer emptyTextCache()()
+	var (
+		engine = &countingEngine{Engine: softrender.New(100, 100)}
+		name   = ui.NewStringValue("Player 1")
+		label  = ui.NewLabel(ui.Label{Binding: name})
+	)
+
+	label.Compute(engine)
+	label.Compute(engine)
+	if engine.measured != 1 || label.Value() != "Player 1" {
+		t.Errorf("expected one measurement of Player 1, got %d of %s", engine.measured, label.Value())
+	}
+
+	name.Set("Player 2")
+	label.Compute(engine)
+	label.Compute(engine)
+	if engine.measured != 2 || label.Value() != "Player 2" {
+		t.Errorf("expected two measurements of Player 2, got %d of %s", engine.measured, label.Value())
+	}
+}
+
+// Clicking a bound CheckButton sets its value and fires Change.
+func TestCheckButtonBinding(t *testing.T) {
+	var (
+		engine  = softrender.New(100, 100)
+		sound   = ui.NewBoolValue(false)
+		device  = ui.NewStringValue("speakers")
+		check   = ui.NewCheckButtonValue("Sound", sound, ui.NewLabel(ui.Label{Text: "Sound"}))
+		radio   = ui.NewRadioButtonValue("Headphones", device, "headphones", ui.NewLabel(ui.Label{Text: "Headphones"}))
+		changes []interface{}
+	)
+	for _, w := range []*ui.CheckButton{check, radio} {
+		w.Handle(ui.Change, func(ed ui.EventData) error {
+			changes = append(changes, ed.Value)
+			return nil
+		})
+	}
+
+	check.Event(ui.Click, ui.EventData{})
+	radio.Event(ui.Click, ui.EventData{})
+	if !sound.Get() || device.Get() != "headphones" {
+		t.Errorf("expected the clicks to set the values, got %v and %s", sound.Get(), device.Get())
+	}
+	if fmt.Sprint(changes) != "[true headphones]" {
+		t.Errorf("expected Change events with the new values, got %v", changes)
+	}
+
+	// Changed elsewhere: the next Compute restyles the button.
+	sound.Set(false)
+	check.Compute(engine)
+	if check.BorderStyle() != ui.BorderRaised {
+		t.Errorf("expected a raised border after the value was unset")
+	}
+}
+
+// A ListBox's selection follows its Binding both ways.
+func TestListBoxBinding(t *testing.T) {
+	var (
+		engine   = softrender.New(100, 100)
+		selected = ui.NewValue("b")
+		list     = ui.NewListBox("List", ui.ListBox{Binding: selected})
+	)
+	for _, value := range []string{"a", "b", "c"} {
+		list.AddLabel(value, value, nil)
+	}
+	if row, ok := list.GetValue(); !ok || row.Value != "b" {
+		t.Errorf("expected the bound value to be selected")
+	}
+
+	selected.Set("c")
+	list.Compute(engine)
+	if row, ok := list.GetValue(); !ok || row.Value != "c" {
+		t.Errorf("expected the selection to follow the binding")
+	}
+
+	list.SetValue("a")
+	if selected.Get() != "a" {
+		t.Errorf("expected SetValue to update the binding, got %v", selected.Get())
+	}
+}
@@ -34,6 +34,7 @@ func NewButton(name string, child Widget) *Button {
 	w.IDFunc(func() string {
 		return fmt.Sprintf("Button<%s>", w.Name)
 	})
+	w.SetFocusable(true)
 
 	w.SetStyle(Theme.Button)
 
@@ -9,12 +9,17 @@ import (
 
 // CheckButton implements a checkbox and radiobox widget. It's based on a
 // Button and holds a boolean or string pointer (boolean for checkbox,
-// string for radio).
+// string for radio), or an observable BoolValue or StringValue.
 type CheckButton struct {
 	Button
-	BoolVar   *bool
-	StringVar *string
-	Value     string
+	BoolVar       *bool
+	StringVar     *string
+	BoolBinding   *BoolValue
+	StringBinding *StringValue
+	Value         string
+
+	bound    boundValue
+	computed bool // whether it was checked on the last Compute
 }
 
 // NewCheckButton creates a new CheckButton.
@@ -26,6 +31,42 @@ func NewCheckButton(name string, boolVar *bool, child Widget) *CheckButton {
 	w.IDFunc(func() string {
 		return fmt.Sprintf("CheckButton<%s %+v>", name, w.BoolVar)
 	})
+	w.SetFocusable(true)
+
+	w.SetStyle(Theme.Button)
+
+	w.setup()
+	return w
+}
+
+// NewCheckButtonValue creates a CheckButton bound to an observable bool.
+func NewCheckButtonValue(name string, v *BoolValue, child Widget) *CheckButton {
+	w := &CheckButton{
+		BoolBinding: v,
+	}
+	w.Button.child = child
+	w.IDFunc(func() string {
+		return fmt.Sprintf("CheckButton<%s %+v>", name, w.BoolBinding.Get())
+	})
+	w.SetFocusable(true)
+
+	w.SetStyle(Theme.Button)
+
+	w.setup()
+	return w
+}
+
+// NewRadioButtonValue creates a CheckButton bound to an observable string.
+func NewRadioButtonValue(name string, v *StringValue, value string, child Widget) *CheckButton {
+	w := &CheckButton{
+		StringBinding: v,
+		Value:         value,
+	}
+	w.Button.child = child
+	w.IDFunc(func() string {
+		return fmt.Sprintf(`RadioButton<%s "%s" %s>`, name, w.Value, strconv.FormatBool(w.checked()))
+	})
+	w.SetFocusable(true)
 
 	w.SetStyle(Theme.Button)
 
@@ -43,6 +84,7 @@ func NewRadioButton(name string, stringVar *string, value string, child Widget)
 	w.IDFunc(func() string {
 		return fmt.Sprintf(`RadioButton<%s "%s" %s>`, name, w.Value, strconv.FormatBool(*w.StringVar == w.Value))
 	})
+	w.SetFocusable(true)
 
 	w.SetStyle(Theme.Button)
 
@@ -53,37 +95,87 @@ func NewRadioButton(name string, stringVar *string, value string, child Widget)
 // Compute to re-evaluate the button state (in the case of radio buttons where
 // a different button will affect the state of this one when clicked).
 func (w *CheckButton) Compute(e render.Engine) {
-	if w.StringVar != nil {
-		// Radio button, always re-assign the border style in case a sister
-		// radio button has changed the value.
-		if *w.StringVar == w.Value {
+	// Always re-assign the border style in case a sister radio button has
+	// changed the value, or the caller has flipped the boolean behind our
+	// back. An observable value only needs it when it changed.
+	if observable := w.observable(); observable == nil || w.bound.changed(observable) {
+		if w.checked() {
 			w.SetBorderStyle(BorderSunken)
-		} else {
-			w.SetBorderStyle(BorderRaised)
-		}
-	} else if w.BoolVar != nil {
-		// Checkbutton, always re-assign the border style in case the caller
-		// has flipped the boolean behind our back.
-		if *w.BoolVar {
-			w.SetBorderStyle(BorderSunken)
-		} else {
+		} else if w.BoolVar != nil || w.StringVar != nil || observable != nil {
 			w.SetBorderStyle(BorderRaised)
 		}
 	}
+	w.computed = w.checked()
 	w.Button.Compute(e)
 }
 
+// Dirty returns whether the CheckButton changed since it was last drawn,
+// including a change of its variable.
+func (w *CheckButton) Dirty() bool {
+	return w.Button.Dirty() || w.checked() != w.computed
+}
+
+// Destroy unsubscribes the CheckButton from its BoolBinding or StringBinding.
+func (w *CheckButton) Destroy() {
+	w.bound.release()
+	w.Button.Destroy()
+}
+
+// observable returns the observable value of the CheckButton, or nil.
+func (w *CheckButton) observable() Observable {
+	if w.BoolBinding != nil {
+		return w.BoolBinding
+	} else if w.StringBinding != nil {
+		return w.StringBinding
+	}
+	return nil
+}
+
+// checked returns whether the CheckButton is on: its boolean is true, or its
+// string has the radio button's Value.
+func (w *CheckButton) checked() bool {
+	switch {
+	case w.BoolBinding != nil:
+		return w.BoolBinding.Get()
+	case w.StringBinding != nil:
+		return w.StringBinding.Get() == w.Value
+	case w.BoolVar != nil:
+		return *w.BoolVar
+	case w.StringVar != nil:
+		return *w.StringVar == w.Value
+	}
+	return false
+}
+
+// check updates the bound value for a click: a checkbox is toggled and a
+// radio button takes its Value. Returns the new value.
+func (w *CheckButton) check() interface{} {
+	switch {
+	case w.BoolBinding != nil:
+		w.BoolBinding.Toggle()
+		return w.BoolBinding.Get()
+	case w.StringBinding != nil:
+		w.StringBinding.Set(w.Value)
+		return w.Value
+	case w.BoolVar != nil:
+		*w.BoolVar = !*w.BoolVar
+		return *w.BoolVar
+	case w.StringVar != nil:
+		*w.StringVar = w.Value
+		return w.Value
+	}
+	return nil
+}
+
 // setup the common things between checkboxes and radioboxes.
 func (w *CheckButton) setup() {
 	var (
 		borderStyle BorderStyle = BorderRaised
 		background              = w.style.Background
 	)
-	if w.BoolVar != nil {
-		if *w.BoolVar == true {
-			borderStyle = BorderSunken
-			background = w.style.Background.Darken(40)
-		}
+	if w.checked() {
+		borderStyle = BorderSunken
+		background = w.style.Background.Darken(40)
 	}
 
 	w.Configure(Config{
@@ -102,14 +194,7 @@ func (w *CheckButton) setup() {
 	w.Handle(MouseOut, func(ed EventData) error {
 		w.hovering = false
 
-		var sunken bool
-		if w.BoolVar != nil {
-			sunken = *w.BoolVar == true
-		} else if w.StringVar != nil {
-			sunken = *w.StringVar == w.Value
-		}
-
-		if sunken {
+		if w.checked() {
 			w.SetBackground(w.style.Background.Darken(40))
 		} else {
 			w.SetBackground(w.style.Background)
@@ -129,20 +214,9 @@ func (w *CheckButton) setup() {
 	})
 
 	w.Handle(Click, func(ed EventData) error {
-		var sunken bool
-		if w.BoolVar != nil {
-			if *w.BoolVar {
-				*w.BoolVar = false
-			} else {
-				*w.BoolVar = true
-				sunken = true
-			}
-		} else if w.StringVar != nil {
-			*w.StringVar = w.Value
-			sunken = true
-		}
+		value := w.check()
 
-		if sunken {
+		if w.checked() {
 			w.SetBorderStyle(BorderSunken)
 			w.SetBackground(w.style.Background.Darken(40))
 		} else {
@@ -150,6 +224,8 @@ func (w *CheckButton) setup() {
 			w.SetBackground(w.style.Background)
 		}
 
-		return nil
+		// Tell the Change handlers about the new value.
+		ed.Value = value
+		return w.Event(Change, ed)
 	})
 }
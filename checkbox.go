@@ -11,16 +11,35 @@ type Checkbox struct {
 
 // NewCheckbox creates a new Checkbox.
 func NewCheckbox(name string, boolVar *bool, child Widget) *Checkbox {
-	return makeCheckbox(name, boolVar, nil, "", child)
+	return makeCheckbox(name, func(mark Widget) *CheckButton {
+		return NewCheckButton(name+"_button", boolVar, mark)
+	}, child)
 }
 
 // NewRadiobox creates a new Checkbox in radio mode.
 func NewRadiobox(name string, stringVar *string, value string, child Widget) *Checkbox {
-	return makeCheckbox(name, nil, stringVar, value, child)
+	return makeCheckbox(name, func(mark Widget) *CheckButton {
+		return NewRadioButton(name+"_button", stringVar, value, mark)
+	}, child)
+}
+
+// NewCheckboxValue creates a new Checkbox bound to an observable bool.
+func NewCheckboxValue(name string, v *BoolValue, child Widget) *Checkbox {
+	return makeCheckbox(name, func(mark Widget) *CheckButton {
+		return NewCheckButtonValue(name+"_button", v, mark)
+	}, child)
+}
+
+// NewRadioboxValue creates a new Checkbox in radio mode bound to an
+// observable string.
+func NewRadioboxValue(name string, v *StringValue, value string, child Widget) *Checkbox {
+	return makeCheckbox(name, func(mark Widget) *CheckButton {
+		return NewRadioButtonValue(name+"_button", v, value, mark)
+	}, child)
 }
 
 // makeCheckbox constructs an appropriate type of checkbox.
-func makeCheckbox(name string, boolVar *bool, stringVar *string, value string, child Widget) *Checkbox {
+func makeCheckbox(name string, newButton func(mark Widget) *CheckButton, child Widget) *Checkbox {
 	// Our custom checkbutton widget.
 	mark := NewFrame(name + "_mark")
 	mark.Configure(Config{
@@ -29,12 +48,8 @@ func makeCheckbox(name string, boolVar *bool, stringVar *string, value string, c
 	})
 
 	w := &Checkbox{
-		child: child,
-	}
-	if boolVar != nil {
-		w.button = NewCheckButton(name+"_button", boolVar, mark)
-	} else if stringVar != nil {
-		w.button = NewRadioButton(name+"_button", stringVar, value, mark)
+		child:  child,
+		button: newButton(mark),
 	}
 	w.Frame.Setup()
 
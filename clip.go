@@ -0,0 +1,298 @@
+package ui
+
+import (
+	"image"
+	"math"
+
+	"git.kirsle.net/go/render"
+)
+
+/*
+clip.go holds the ClipEngine, which keeps drawing inside a clip rectangle.
+
+The render.Engine has no clipping of its own, so containers that show only
+part of their children, like the ScrollFrame, present them through a
+ClipEngine instead: it clips the drawing primitives to the topmost rect of
+its clip stack and passes them on to the real engine.
+*/
+
+// ClipEngine wraps a render.Engine to clip its drawing to a stack of clip
+// rectangles. Push a rect with PushClip before presenting widgets through
+// the ClipEngine, and PopClip it afterwards; a pushed rect is intersected
+// with the one below it, so nested containers clip to their common area.
+//
+// Points, lines, rects, boxes and textures are clipped exactly. Text that is
+// partly inside the clip rect is drawn from a texture when the engine can
+// render one (see TextRenderer and TextCache), and otherwise in full.
+type ClipEngine struct {
+	render.Engine
+	clips []render.Rect
+}
+
+// textRendererEngine is an engine that renders text to images, like the
+// softrender Engine.
+type textRendererEngine interface {
+	RenderText(render.Text) (image.Image, error)
+}
+
+// Clip returns a ClipEngine over the engine, or the engine itself if it is
+// a ClipEngine already, so its clip stack is shared.
+func Clip(e render.Engine) *ClipEngine {
+	if ce, ok := e.(*ClipEngine); ok {
+		return ce
+	}
+	return &ClipEngine{Engine: e}
+}
+
+// unwrapEngine returns the engine under a ClipEngine.
+func unwrapEngine(e render.Engine) render.Engine {
+	if ce, ok := e.(*ClipEngine); ok {
+		return ce.Engine
+	}
+	return e
+}
+
+// PushClip pushes a clip rect, limited to the current clip rect.
+func (e *ClipEngine) PushClip(r render.Rect) {
+	if top, ok := e.ClipRect(); ok {
+		r = intersectRect(top, r)
+	}
+	e.clips = append(e.clips, r)
+}
+
+// PopClip removes the clip rect pushed last.
+func (e *ClipEngine) PopClip() {
+	if len(e.clips) > 0 {
+		e.clips = e.clips[:len(e.clips)-1]
+	}
+}
+
+// ClipRect returns the current clip rect, or false if none was pushed and
+// drawing isn't clipped.
+func (e *ClipEngine) ClipRect() (render.Rect, bool) {
+	if len(e.clips) == 0 {
+		return render.Rect{}, false
+	}
+	return e.clips[len(e.clips)-1], true
+}
+
+// Visible returns whether any part of a rect is inside the clip rect.
+func (e *ClipEngine) Visible(r render.Rect) bool {
+	clip, ok := e.ClipRect()
+	if !ok {
+		return r.W > 0 && r.H > 0
+	}
+	R := intersectRect(clip, r)
+	return R.W > 0 && R.H > 0
+}
+
+// contains returns whether a rect is entirely inside the clip rect.
+func (e *ClipEngine) contains(r render.Rect) bool {
+	clip, ok := e.ClipRect()
+	if !ok {
+		return true
+	}
+	return intersectRect(clip, r) == r
+}
+
+// DrawPoint draws a pixel inside the clip rect.
+func (e *ClipEngine) DrawPoint(c render.Color, p render.Point) {
+	if clip, ok := e.ClipRect(); !ok || p.Inside(clip) {
+		e.Engine.DrawPoint(c, p)
+	}
+}
+
+// DrawLine draws the part of a line inside the clip rect.
+func (e *ClipEngine) DrawLine(c render.Color, a, b render.Point) {
+	clip, ok := e.ClipRect()
+	if !ok {
+		e.Engine.DrawLine(c, a, b)
+		return
+	}
+	if a, b, ok = clipLine(clip, a, b); ok {
+		e.Engine.DrawLine(c, a, b)
+	}
+}
+
+// DrawRect draws the part of a rect's outline inside the clip rect.
+func (e *ClipEngine) DrawRect(c render.Color, r render.Rect) {
+	if r.W <= 0 || r.H <= 0 {
+		return
+	}
+	if e.contains(r) {
+		e.Engine.DrawRect(c, r)
+		return
+	}
+
+	// Draw the sides as boxes, clipped one by one.
+	e.DrawBox(c, render.Rect{X: r.X, Y: r.Y, W: r.W, H: 1})
+	if r.H > 1 {
+		e.DrawBox(c, render.Rect{X: r.X, Y: r.Y + r.H - 1, W: r.W, H: 1})
+	}
+	if r.H > 2 {
+		e.DrawBox(c, render.Rect{X: r.X, Y: r.Y + 1, W: 1, H: r.H - 2})
+		if r.W > 1 {
+			e.DrawBox(c, render.Rect{X: r.X + r.W - 1, Y: r.Y + 1, W: 1, H: r.H - 2})
+		}
+	}
+}
+
+// DrawBox draws the part of a filled rect inside the clip rect.
+func (e *ClipEngine) DrawBox(c render.Color, r render.Rect) {
+	if clip, ok := e.ClipRect(); ok {
+		r = intersectRect(clip, r)
+	}
+	if r.W > 0 && r.H > 0 {
+		e.Engine.DrawBox(c, r)
+	}
+}
+
+// Copy draws the part of a texture that lands inside the clip rect.
+func (e *ClipEngine) Copy(t render.Texturer, src, dst render.Rect) {
+	clip, ok := e.ClipRect()
+	if !ok || intersectRect(clip, dst) == dst {
+		e.Engine.Copy(t, src, dst)
+		return
+	}
+
+	R := intersectRect(clip, dst)
+	if R.W <= 0 || R.H <= 0 || dst.W <= 0 || dst.H <= 0 {
+		return
+	}
+
+	// Crop the source by the same fraction as the destination.
+	var (
+		x1 = src.X + (R.X-dst.X)*src.W/dst.W
+		y1 = src.Y + (R.Y-dst.Y)*src.H/dst.H
+		x2 = src.X + (R.X+R.W-dst.X)*src.W/dst.W
+		y2 = src.Y + (R.Y+R.H-dst.Y)*src.H/dst.H
+	)
+	if x2 > x1 && y2 > y1 {
+		e.Engine.Copy(t, render.Rect{X: x1, Y: y1, W: x2 - x1, H: y2 - y1}, R)
+	}
+}
+
+// DrawText draws a line of text that is inside the clip rect. Text partly
+// inside it is drawn from a texture of the DefaultTextCache, rendered by
+// its Renderer or by the engine if it has a RenderText method.
+func (e *ClipEngine) DrawText(text render.Text, p render.Point) error {
+	if _, ok := e.ClipRect(); !ok {
+		return e.Engine.DrawText(text, p)
+	}
+
+	rect, err := DefaultTextCache.ComputeTextRect(e.Engine, text)
+	if err != nil {
+		return err
+	}
+
+	// Leave a pixel around the text for its stroke and shadow.
+	var bounds = render.Rect{
+		X: p.X - 1,
+		Y: p.Y - 1,
+		W: rect.W + 2,
+		H: rect.H + 2,
+	}
+	if e.contains(bounds) {
+		return e.Engine.DrawText(text, p)
+	} else if !e.Visible(bounds) {
+		return nil
+	}
+
+	var renderer = DefaultTextCache.renderer(e.Engine)
+	if renderer == nil {
+		return e.Engine.DrawText(text, p)
+	}
+	return DefaultTextCache.drawText(e, renderer, text, p)
+}
+
+// intersectRect returns the area two rects have in common, which is empty
+// (zero width or height) if they don't overlap.
+func intersectRect(a, b render.Rect) render.Rect {
+	var (
+		x1 = maxInt(a.X, b.X)
+		y1 = maxInt(a.Y, b.Y)
+		x2 = minInt(a.X+a.W, b.X+b.W)
+		y2 = minInt(a.Y+a.H, b.Y+b.H)
+	)
+	if x2 <= x1 || y2 <= y1 {
+		return render.Rect{X: x1, Y: y1}
+	}
+	return render.Rect{X: x1, Y: y1, W: x2 - x1, H: y2 - y1}
+}
+
+// unionRect returns the rect that covers both rects. An empty rect (with no
+// width or height) covers nothing.
+func unionRect(a, b render.Rect) render.Rect {
+	if a.W <= 0 || a.H <= 0 {
+		return b
+	} else if b.W <= 0 || b.H <= 0 {
+		return a
+	}
+
+	var (
+		x1 = minInt(a.X, b.X)
+		y1 = minInt(a.Y, b.Y)
+		x2 = maxInt(a.X+a.W, b.X+b.W)
+		y2 = maxInt(a.Y+a.H, b.Y+b.H)
+	)
+	return render.Rect{X: x1, Y: y1, W: x2 - x1, H: y2 - y1}
+}
+
+// clipLine clips the line from a to b (inclusive) to a rect, with the
+// Liang-Barsky algorithm. Returns false if no part of it is inside.
+func clipLine(r render.Rect, a, b render.Point) (render.Point, render.Point, bool) {
+	if r.W <= 0 || r.H <= 0 {
+		return a, b, false
+	}
+
+	var (
+		dx     = float64(b.X - a.X)
+		dy     = float64(b.Y - a.Y)
+		t0, t1 = 0.0, 1.0
+		edges  = []struct{ p, q float64 }{
+			{-dx, float64(a.X - r.X)},
+			{dx, float64(r.X + r.W - 1 - a.X)},
+			{-dy, float64(a.Y - r.Y)},
+			{dy, float64(r.Y + r.H - 1 - a.Y)},
+		}
+	)
+	for _, edge := range edges {
+		if edge.p == 0 {
+			if edge.q < 0 {
+				return a, b, false
+			}
+			continue
+		}
+
+		t := edge.q / edge.p
+		if edge.p < 0 {
+			if t > t1 {
+				return a, b, false
+			} else if t > t0 {
+				t0 = t
+			}
+		} else {
+			if t < t0 {
+				return a, b, false
+			} else if t < t1 {
+				t1 = t
+			}
+		}
+	}
+
+	point := func(t float64) render.Point {
+		return render.NewPoint(
+			a.X+int(math.Round(t*dx)),
+			a.Y+int(math.Round(t*dy)),
+		)
+	}
+	return point(t0), point(t1), true
+}
+
+// minInt returns the smaller of two ints.
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,75 @@
+package ui_test
+
+import (
+	"testing"
+
+	"git.kirsle.net/go/render"
+	"git.kirsle.net/go/ui"
+	"git.kirsle.net/go/ui/softrender"
+)
+
+// pixelIs returns whether a pixel of a softrender engine has the color.
+func pixelIs(e *softrender.Engine, x, y int, c render.Color) bool {
+	p := e.Image().RGBAAt(x, y)
+	return p.R == c.Red && p.G == c.Green && p.B == c.Blue && p.A == c.Alpha
+}
+
+func TestClipEngine(t *testing.T) {
+	defer emptyTextCache()()
+	var (
+		engine = softrender.New(40, 20)
+		clip   = ui.Clip(engine)
+	)
+	engine.Clear(testBackground)
+	if ui.Clip(clip) != clip {
+		t.Errorf("expected Clip to reuse a ClipEngine")
+	}
+
+	// Nested clip rects are intersected.
+	clip.PushClip(render.Rect{X: 5, Y: 5, W: 10, H: 10})
+	clip.PushClip(render.Rect{W: 10, H: 10})
+	clip.DrawBox(testHighlight, render.NewRect(40, 20))
+	for _, test := range []struct {
+		x, y   int
+		expect render.Color
+	}{
+		{5, 5, testHighlight},
+		{9, 9, testHighlight},
+		{4, 5, testBackground},
+		{10, 9, testBackground},
+	} {
+		if !pixelIs(engine, test.x, test.y, test.expect) {
+			t.Errorf("box: expected %d,%d to be %v", test.x, test.y, test.expect)
+		}
+	}
+
+	clip.PopClip()
+	clip.DrawLine(testForeground, render.NewPoint(0, 12), render.NewPoint(39, 12))
+	if !pixelIs(engine, 5, 12, testForeground) || !pixelIs(engine, 14, 12, testForeground) ||
+		!pixelIs(engine, 4, 12, testBackground) || !pixelIs(engine, 15, 12, testBackground) {
+		t.Errorf("expected the line to be clipped to x=5..14")
+	}
+
+	// Text partly inside the clip rect is cut off at its edge.
+	engine.Clear(testBackground)
+	clip.PopClip()
+	clip.PushClip(render.NewRect(10, 20))
+	clip.DrawText(render.Text{Text: "Hello", Size: 12, Color: testForeground}, render.Point{})
+	var inside, outside bool
+	for y := 0; y < 20; y++ {
+		for x := 0; x < 40; x++ {
+			if !pixelIs(engine, x, y, testBackground) {
+				inside = inside || x < 10
+				outside = outside || x >= 10
+			}
+		}
+	}
+	if !inside || outside {
+		t.Errorf("expected text only inside the clip rect, got inside=%v outside=%v", inside, outside)
+	}
+
+	clip.PopClip()
+	if _, ok := clip.ClipRect(); ok {
+		t.Errorf("expected no clip rect after popping them all")
+	}
+}
@@ -321,16 +321,27 @@ func (w *ColorPicker) setup() error {
 }
 
 // Compute the widget.
-func (w *ColorPicker) Compute(e render.Engine) {
-	// TODO: free the w.tex
-}
+func (w *ColorPicker) Compute(e render.Engine) {}
 
 // Destroy the ColorPicker widget. Call this instead of Hide() if you close the
-// widget programmatically! It will free up SDL textures and so on.
+// widget programmatically! The picker may be shown again afterwards.
+//
+// When you're done with the picker for good, DestroyTree(picker) also frees
+// its SDL textures and removes its window from the Supervisor.
 func (w *ColorPicker) Destroy() {
 	w.Hide()
 }
 
+// teardown frees the gradient texture and removes the window from the
+// Supervisor, when the picker is destroyed by DestroyTree.
+func (w *ColorPicker) teardown() {
+	if w.tex != nil {
+		w.tex.Free()
+		w.tex = nil
+	}
+	w.Supervisor.RemoveTree(w.Window)
+}
+
 // ColorPickerPreset shows the preset color buttons.
 // Suggested to have 18 colors which is displayed in 2 rows of 9 buttons.
 // More presets may need larger than default window size to fit.
@@ -0,0 +1,40 @@
+package ui_test
+
+import (
+	"testing"
+
+	"git.kirsle.net/go/ui"
+	"git.kirsle.net/go/ui/softrender"
+)
+
+// Destroy only closes the picker so it can be shown again, while DestroyTree
+// frees its texture for good.
+func TestColorPickerDestroy(t *testing.T) {
+	var (
+		engine = softrender.New(400, 300)
+		s      = ui.NewSupervisor()
+	)
+	picker, err := ui.NewColorPicker(ui.ColorPicker{
+		Supervisor: s,
+		Engine:     engine,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	const spectrum = "ui.ColorPicker/spectrum.png"
+
+	picker.Show()
+	picker.Destroy()
+	picker.Show()
+	if picker.Hidden() {
+		t.Errorf("expected the picker to show again after Destroy")
+	}
+	if _, err := engine.LoadTexture(spectrum); err != nil {
+		t.Errorf("expected Destroy to keep the texture: %s", err)
+	}
+
+	ui.DestroyTree(picker)
+	if _, err := engine.LoadTexture(spectrum); err == nil {
+		t.Errorf("expected DestroyTree to free the texture")
+	}
+}
@@ -0,0 +1,300 @@
+package ui
+
+import (
+	"git.kirsle.net/go/render"
+	"git.kirsle.net/go/render/event"
+)
+
+/*
+dispatch.go holds the DOM-style propagation of mouse events through the parent
+chain of the widget under the cursor.
+
+Mouse events are dispatched to the deepest widget under the cursor (the
+Target), which need not be added to the Supervisor itself so long as one of
+its ancestors was. The event goes through three phases:
+
+ 1. Capture: from the top-most ancestor down to the target, the handlers
+    added with HandleCapture are called.
+ 2. Target: the target's own handlers added with Handle are called.
+ 3. Bubble: from the target up to the top-most ancestor, the handlers added
+    with HandleBubble are called.
+
+Any handler may call EventData.StopPropagation (or return ErrStopPropagation)
+to end the dispatch, or EventData.PreventDefault to stop the Supervisor's
+default action for the event, such as giving keyboard focus on MouseDown.
+*/
+
+// EventPhase is the phase of a propagating event.
+type EventPhase int
+
+// Event phases. Events sent directly to a widget are in the TargetPhase.
+const (
+	TargetPhase EventPhase = iota
+	CapturePhase
+	BubblePhase
+)
+
+// propagation is the state of an event being dispatched, shared by all the
+// copies of its EventData.
+type propagation struct {
+	stopped   bool
+	prevented bool
+}
+
+// StopPropagation stops the event from reaching any further handlers in the
+// capture or bubble phases.
+func (ed EventData) StopPropagation() {
+	if ed.propagation != nil {
+		ed.propagation.stopped = true
+	}
+}
+
+// PreventDefault stops the Supervisor's default action for the event, such
+// as giving the keyboard focus to a widget on MouseDown.
+func (ed EventData) PreventDefault() {
+	if ed.propagation != nil {
+		ed.propagation.prevented = true
+	}
+}
+
+// DefaultPrevented returns whether a handler has called PreventDefault.
+func (ed EventData) DefaultPrevented() bool {
+	return ed.propagation != nil && ed.propagation.prevented
+}
+
+// Dispatch sends an event to the target widget through the capture, target
+// and bubble phases. Returns true if a handler called PreventDefault.
+func (s *Supervisor) Dispatch(target Widget, event Event, ed EventData) bool {
+	_, prevented := s.propagate(target, event, ed, true)
+	return prevented
+}
+
+// propagate dispatches an event through the parent chain of the target. If
+// toTarget is false, the target's own handlers are skipped (for widgets that
+// were already sent the event directly by runWidgetEvents).
+func (s *Supervisor) propagate(target Widget, event Event, ed EventData, toTarget bool) (stopped, prevented bool) {
+	var (
+		state = &propagation{}
+		path  = []Widget{target} // the target and its ancestors
+	)
+	for node := target; ; {
+		parent, ok := node.Parent()
+		if !ok || parent == nil {
+			break
+		}
+		path = append(path, parent)
+		node = parent
+	}
+
+	ed.Supervisor = s
+	ed.Target = target
+	ed.propagation = state
+
+	run := func(w Widget, phase EventPhase) bool {
+		if h, ok := w.(interface {
+			phaseEvent(EventPhase, Event, EventData) error
+		}); ok {
+			ed.Widget = w
+			ed.Phase = phase
+			if h.phaseEvent(phase, event, ed) == ErrStopPropagation {
+				state.stopped = true
+			}
+		}
+		return state.stopped
+	}
+
+	// Capture phase: from the top down.
+	for i := len(path) - 1; i >= 0; i-- {
+		if run(path[i], CapturePhase) {
+			return true, state.prevented
+		}
+	}
+
+	// Target phase.
+	if toTarget {
+		ed.Widget = target
+		ed.Phase = TargetPhase
+		if target.Event(event, ed) == ErrStopPropagation || state.stopped {
+			return true, state.prevented
+		}
+	}
+
+	// Bubble phase: from the bottom up.
+	for _, w := range path {
+		if run(w, BubblePhase) {
+			return true, state.prevented
+		}
+	}
+
+	return false, state.prevented
+}
+
+// hitTest returns the deepest visible widget under the cursor: starting from
+// the innermost supervised widget that may receive mouse events, it descends
+// into the children whose boxes hold the cursor. Returns nil if the cursor is
+// over no widget.
+func (s *Supervisor) hitTest(XY render.Point, hovering []WidgetSlot) Widget {
+	var node = s.innermostWidget(XY, hovering)
+	if node == nil {
+		return nil
+	}
+
+	for {
+		var (
+			children = node.Children()
+			next     Widget
+		)
+
+		// Later children are drawn on top of earlier ones.
+		for i := len(children) - 1; i >= 0; i-- {
+			child := children[i]
+			if !child.Hidden() && XY.Inside(AbsoluteRect(child)) {
+				next = child
+				break
+			}
+		}
+
+		if next == nil {
+			return node
+		}
+		node = next
+	}
+}
+
+// innermostWidget returns the most deeply nested of the hovered widgets
+// that may receive mouse events, or nil. If two widgets are equally deep,
+// the one added to the Supervisor last wins.
+func (s *Supervisor) innermostWidget(XY render.Point, hovering []WidgetSlot) Widget {
+	var (
+		modal     = s.GetModal()
+		inWindow  = s.winFocus != nil && !s.winFocus.window.Hidden() && XY.Inside(AbsoluteRect(s.winFocus.window))
+		innermost Widget
+		deepest   = -1
+	)
+
+	for _, child := range hovering {
+		var w = child.widget
+		if w.Hidden() {
+			continue
+		}
+
+		// Modals and the focused window block the widgets behind them.
+		if modal != nil {
+			if w != modal && !HasParent(w, modal) {
+				continue
+			}
+		} else if isManaged, isFocused := widgetInFocusedWindow(w); inWindow && !(isManaged && isFocused) {
+			continue
+		}
+
+		if depth := widgetDepth(w); depth >= deepest {
+			deepest = depth
+			innermost = w
+		}
+	}
+
+	return innermost
+}
+
+// widgetDepth returns the number of ancestors of a widget.
+func widgetDepth(w Widget) int {
+	var depth int
+	for {
+		parent, ok := w.Parent()
+		if !ok || parent == nil {
+			return depth
+		}
+		depth++
+		w = parent
+	}
+}
+
+// clickEvents are the click events of the mouse buttons.
+var clickEvents = map[MouseButton]Event{
+	MouseButtonLeft:   Click,
+	MouseButtonRight:  RightClick,
+	MouseButtonMiddle: MiddleClick,
+}
+
+// loopPropagation is a subroutine of Supervisor.Loop that dispatches the
+// mouse events through the parent chain of the widget under the cursor.
+//
+// Widgets that were added to the Supervisor are sent their events directly
+// by runWidgetEvents, so their own handlers are skipped in the target phase:
+// they are called after the dispatch, by the Supervisor's usual delivery.
+func (s *Supervisor) loopPropagation(ev *event.State, XY render.Point, hovering []WidgetSlot) {
+	var target = s.hitTest(XY, hovering)
+
+	send := func(w Widget, event Event, ed EventData) bool {
+		if w == nil {
+			return false
+		}
+		s.lock.RLock()
+		_, isSupervised := s.ids[w]
+		s.lock.RUnlock()
+		ed.Point = XY
+		_, prevented := s.propagate(w, event, ed, !isSupervised)
+		return prevented
+	}
+
+	// The cursor has moved onto a different widget.
+	if target != s.hoverTarget {
+		send(s.hoverTarget, MouseOut, EventData{})
+		send(target, MouseOver, EventData{})
+		s.hoverTarget = target
+	}
+
+	// Mouse buttons.
+	s.focusPrevented = false
+	s.clicks = s.clicks[:0]
+	for _, mb := range []struct {
+		Button MouseButton
+		Down   bool
+	}{
+		{MouseButtonLeft, ev.Button1},
+		{MouseButtonRight, ev.Button2},
+		{MouseButtonMiddle, ev.Button3},
+	} {
+		downTarget, isDown := s.downTarget[mb.Button]
+		if mb.Down && !isDown {
+			// Newly pressed.
+			s.downTarget[mb.Button] = target
+			if mb.Button == MouseButtonLeft {
+				s.focusPrevented = send(target, MouseDown, EventData{
+					Button: mb.Button,
+				})
+			}
+		} else if !mb.Down && isDown {
+			// Released: a click if it's over the same widget it was pressed on.
+			delete(s.downTarget, mb.Button)
+			if mb.Button == MouseButtonLeft {
+				send(target, MouseUp, EventData{
+					Button: mb.Button,
+				})
+			}
+
+			if target != nil && target == downTarget {
+				ed := EventData{
+					Button: mb.Button,
+				}
+				if mb.Button == MouseButtonLeft {
+					ed.ClickCount = s.clickCount
+				}
+				send(target, clickEvents[mb.Button], ed)
+				if mb.Button == MouseButtonLeft && s.clickCount == 2 {
+					send(target, DoubleClick, ed)
+				}
+
+				// The supervised widgets under the cursor get right and
+				// middle clicks from runWidgetEvents.
+				if mb.Button != MouseButtonLeft {
+					s.clicks = append(s.clicks, mb.Button)
+				}
+			}
+		}
+	}
+
+	send(target, MouseMove, EventData{
+		Clicked: ev.Button1,
+	})
+}
@@ -1,11 +1,60 @@
 package ui
 
+import (
+	"git.kirsle.net/go/render"
+	"git.kirsle.net/go/render/event"
+)
+
 // DragDrop is a state machine to manage draggable UI components.
 type DragDrop struct {
 	isDragging bool
 
 	// If the subject of the drag is a widget, it can store itself here.
 	widget Widget
+
+	// Typed drag state, see Supervisor.DragStartPayload.
+	payload  *DragPayload
+	ghost    Widget       // widget drawn under the cursor
+	over     Widget       // drop site under the cursor
+	accepted bool         // the drop site accepts the payload
+	cursor   render.Point // cursor position on the last tick
+	result   DropResult   // result of the last drag
+}
+
+// DragPayload is the data carried by a typed drag. The Kind tells drop sites
+// what is being dragged (e.g. "color" or "doodad") so they can decide
+// whether to accept it, and the Value is the data itself.
+type DragPayload struct {
+	Kind  string
+	Value interface{}
+}
+
+// DropResult tells the source of a typed drag how it ended, on its DragStop
+// event. Target is the drop site that accepted the drop, or nil if the drag
+// was dropped elsewhere or cancelled.
+type DropResult struct {
+	Accepted bool
+	Target   Widget
+	Payload  DragPayload
+}
+
+// DragGhostOffset is where the drag ghost is drawn relative to the cursor.
+var DragGhostOffset = render.NewPoint(8, 8)
+
+// dropState is the answer of a drop site to a DragEnter or DragOver event,
+// shared by the copies of its EventData.
+type dropState struct {
+	accepted bool
+}
+
+// AcceptDrop is called by the handler of a DragEnter or DragOver event to
+// accept the payload being dragged (see EventData.Drag). Drop sites with
+// these handlers that don't call it refuse the drop, while a drop site with
+// only a Drop handler accepts any payload and checks its Kind on Drop.
+func (ed EventData) AcceptDrop() {
+	if ed.drop != nil {
+		ed.drop.accepted = true
+	}
 }
 
 // NewDragDrop initializes the DragDrop struct. Normally your Supervisor
@@ -31,13 +80,216 @@ func (dd *DragDrop) Widget() Widget {
 	return dd.widget
 }
 
+// SetPayload attaches a typed payload to the drag state.
+func (dd *DragDrop) SetPayload(payload DragPayload) {
+	dd.payload = &payload
+}
+
+// Payload returns the typed payload of the drag, or nil.
+func (dd *DragDrop) Payload() *DragPayload {
+	return dd.payload
+}
+
+// SetGhost attaches a widget to be drawn under the cursor during the drag.
+func (dd *DragDrop) SetGhost(w Widget) {
+	dd.ghost = w
+}
+
+// Ghost returns the widget drawn under the cursor, or nil.
+func (dd *DragDrop) Ghost() Widget {
+	return dd.ghost
+}
+
+// Over returns the drop site under the cursor, and whether it accepts the
+// payload.
+func (dd *DragDrop) Over() (Widget, bool) {
+	return dd.over, dd.accepted
+}
+
+// Result returns how the last typed drag ended.
+func (dd *DragDrop) Result() DropResult {
+	return dd.result
+}
+
 // Start the drag state.
 func (dd *DragDrop) Start() {
 	dd.isDragging = true
+	dd.result = DropResult{}
 }
 
-// Stop dragging. This will also clear the stored widget, if any.
+// Stop dragging. This will also clear the stored widget, payload and ghost,
+// if any.
 func (dd *DragDrop) Stop() {
 	dd.isDragging = false
 	dd.widget = nil
+	dd.payload = nil
+	dd.ghost = nil
+	dd.over = nil
+	dd.accepted = false
+}
+
+// loopDrag is a subroutine of Supervisor.Loop while a drag is underway.
+func (s *Supervisor) loopDrag(ev *event.State, XY render.Point, hovering []WidgetSlot) {
+	var dd = s.dd
+	dd.cursor = XY
+
+	if ghost := dd.Ghost(); ghost != nil {
+		ghost.MoveTo(render.NewPoint(XY.X+DragGhostOffset.X, XY.Y+DragGhostOffset.Y))
+	}
+
+	if dd.Payload() != nil {
+		s.dragOver(XY, hovering)
+	}
+
+	if ev.Button1 || ev.Button3 {
+		// If we have a target widget being dragged, send it mouse events.
+		if target := dd.Widget(); target != nil {
+			target.Event(DragMove, EventData{
+				Supervisor: s,
+				Widget:     target,
+				Point:      XY,
+			})
+		}
+		return
+	}
+
+	// The mouse has been released. TODO: make mouse button important?
+	if dd.Payload() != nil {
+		s.finishDrag(true)
+		return
+	}
+
+	for _, child := range hovering {
+		child.widget.Event(Drop, EventData{
+			Supervisor: s,
+			Widget:     child.widget,
+			Point:      XY,
+		})
+	}
+
+	var source = dd.Widget()
+	dd.Stop()
+	if source != nil {
+		source.Event(DragStop, EventData{
+			Supervisor: s,
+			Widget:     source,
+			Point:      XY,
+		})
+	}
+}
+
+// dragOver finds the drop site under the cursor during a typed drag and
+// asks it whether it accepts the payload.
+func (s *Supervisor) dragOver(XY render.Point, hovering []WidgetSlot) {
+	var (
+		dd   = s.dd
+		site = s.dropSite(XY, hovering)
+	)
+
+	if site != dd.over {
+		if dd.over != nil {
+			s.sendDragEvent(dd.over, DragLeave)
+		}
+		dd.over, dd.accepted = site, false
+		if site != nil {
+			dd.accepted = s.sendDragEvent(site, DragEnter)
+
+			// A drop site with only a Drop handler takes any payload.
+			if !handlesEvent(site, DragEnter) && !handlesEvent(site, DragOver) {
+				dd.accepted = true
+			}
+		}
+	}
+
+	if site != nil && handlesEvent(site, DragOver) {
+		dd.accepted = s.sendDragEvent(site, DragOver)
+	}
+}
+
+// finishDrag ends a typed drag, dropping the payload on the drop site if it
+// accepts it (and drop is true), and sends the source its DragStop event.
+func (s *Supervisor) finishDrag(drop bool) {
+	var (
+		dd      = s.dd
+		source  = dd.Widget()
+		ghost   = dd.Ghost()
+		site    = dd.over
+		payload = *dd.Payload()
+		result  = DropResult{
+			Payload: payload,
+		}
+	)
+
+	if site != nil {
+		if drop && dd.accepted {
+			s.sendDragEvent(site, Drop)
+			result.Accepted = true
+			result.Target = site
+		} else {
+			s.sendDragEvent(site, DragLeave)
+		}
+	}
+
+	dd.Stop()
+	dd.result = result
+	if ghost != nil {
+		s.removeOnTop(ghost)
+	}
+
+	if source != nil {
+		source.Event(DragStop, EventData{
+			Supervisor: s,
+			Widget:     source,
+			Point:      dd.cursor,
+			Drag:       &result.Payload,
+			DropResult: &result,
+		})
+	}
+}
+
+// sendDragEvent sends a drag event with the payload to a drop site. Returns
+// whether the handler accepted the drop.
+func (s *Supervisor) sendDragEvent(site Widget, e Event) bool {
+	var (
+		payload = *s.dd.Payload()
+		state   = &dropState{}
+	)
+	site.Event(e, EventData{
+		Supervisor: s,
+		Widget:     site,
+		Point:      s.dd.cursor,
+		Drag:       &payload,
+		drop:       state,
+	})
+	return state.accepted
+}
+
+// dropSite returns the deepest widget under the cursor with a handler for
+// DragEnter, DragOver or Drop, or nil.
+func (s *Supervisor) dropSite(XY render.Point, hovering []WidgetSlot) Widget {
+	var (
+		ghost = s.dd.Ghost()
+		node  = s.hitTest(XY, hovering)
+	)
+	for node != nil {
+		if ghost != nil && (node == ghost || HasParent(node, ghost)) {
+			return nil
+		}
+		if handlesEvent(node, DragEnter) || handlesEvent(node, DragOver) || handlesEvent(node, Drop) {
+			return node
+		}
+
+		parent, ok := node.Parent()
+		if !ok {
+			break
+		}
+		node = parent
+	}
+	return nil
+}
+
+// handlesEvent returns whether a widget has a handler for an event.
+func handlesEvent(w Widget, e Event) bool {
+	h, ok := w.(interface{ hasHandler(Event) bool })
+	return ok && h.hasHandler(e)
 }
@@ -0,0 +1,118 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"git.kirsle.net/go/render"
+	"git.kirsle.net/go/render/event"
+)
+
+func TestDragPayload(t *testing.T) {
+	var (
+		s      = NewSupervisor()
+		ev     = event.NewState()
+		source = NewFrame("Swatch")
+		trash  = NewFrame("Trash")
+		list   = NewFrame("List")
+		ghost  = NewFrame("Ghost")
+		log    = []string{}
+		result *DropResult
+	)
+
+	for i, w := range []*Frame{source, trash, list} {
+		w.Resize(render.NewRect(50, 50))
+		w.MoveTo(render.NewPoint(i*100, 0))
+		s.Add(w)
+	}
+
+	// The trash only takes doodads; the list takes colors.
+	for _, site := range []*Frame{trash, list} {
+		site := site
+		accept := "doodad"
+		if site == list {
+			accept = "color"
+		}
+		for _, e := range []Event{DragEnter, DragLeave, Drop} {
+			e := e
+			site.Handle(e, func(ed EventData) error {
+				log = append(log, site.Name+":"+map[Event]string{
+					DragEnter: "enter", DragLeave: "leave", Drop: "drop",
+				}[e])
+				if ed.Drag.Kind == accept {
+					ed.AcceptDrop()
+				}
+				return nil
+			})
+		}
+	}
+	source.Handle(DragStop, func(ed EventData) error {
+		result = ed.DropResult
+		return nil
+	})
+
+	move := func(x int) {
+		ev.CursorX, ev.CursorY = x, 25
+		s.Loop(ev)
+	}
+
+	ev.Button1 = true
+	s.DragStartPayload(source, DragPayload{Kind: "color", Value: render.Red}, ghost)
+	move(25)
+	if p := ghost.Point(); p != render.NewPoint(25+DragGhostOffset.X, 25+DragGhostOffset.Y) {
+		t.Errorf("expected the ghost to follow the cursor, got %s", p)
+	}
+
+	move(125)
+	if _, accepted := s.DragDrop().Over(); accepted {
+		t.Errorf("the trash should refuse a color")
+	}
+	move(225)
+	if over, accepted := s.DragDrop().Over(); over != list || !accepted {
+		t.Errorf("the list should accept a color")
+	}
+
+	ev.Button1 = false
+	move(225)
+
+	expect := "Trash:enter Trash:leave List:enter List:drop"
+	if got := strings.Join(log, " "); got != expect {
+		t.Errorf("expected events %q, got %q", expect, got)
+	}
+	if result == nil || !result.Accepted || result.Target != list || result.Payload.Value != render.Red {
+		t.Errorf("unexpected drop result: %+v", result)
+	}
+	if s.IsDragging() || len(s.onTop) != 0 {
+		t.Errorf("expected the drag to end and the ghost to be removed")
+	}
+
+	// A cancelled drag is not accepted.
+	log = nil
+	ev.Button1 = true
+	s.DragStartPayload(source, DragPayload{Kind: "color"}, nil)
+	move(225)
+	s.DragStop()
+	if result.Accepted || strings.Join(log, " ") != "List:enter List:leave" {
+		t.Errorf("expected a cancelled drag, got %+v and events %v", result, log)
+	}
+
+	// A widget with only a Drop handler takes any payload.
+	var dropped *DragPayload
+	bin := NewFrame("Bin")
+	bin.Resize(render.NewRect(50, 50))
+	bin.MoveTo(render.NewPoint(300, 0))
+	bin.Handle(Drop, func(ed EventData) error {
+		dropped = ed.Drag
+		return nil
+	})
+	s.Add(bin)
+
+	ev.Button1 = true
+	s.DragStartPayload(source, DragPayload{Kind: "doodad"}, nil)
+	move(325)
+	ev.Button1 = false
+	move(325)
+	if dropped == nil || dropped.Kind != "doodad" || !result.Accepted || result.Target != bin {
+		t.Errorf("expected the Drop-only widget to take the payload, got %+v", result)
+	}
+}
@@ -0,0 +1,137 @@
+package ui_test
+
+import (
+	"testing"
+
+	"git.kirsle.net/go/render"
+	"git.kirsle.net/go/ui"
+	"git.kirsle.net/go/ui/drawlog"
+	"git.kirsle.net/go/ui/style"
+)
+
+// Colors for the snapshot tests are spelled out, so the golden files don't
+// depend on the render package's named colors.
+var (
+	testBackground = render.RGBA(200, 200, 200, 255)
+	testForeground = render.RGBA(0, 0, 0, 255)
+	testHighlight  = render.RGBA(255, 255, 0, 255)
+)
+
+// testLabel makes a label with an explicit text color.
+func testLabel(text string) *ui.Label {
+	return ui.NewLabel(ui.Label{
+		Text: text,
+		Font: render.Text{
+			Size:    12,
+			Color:   testForeground,
+			Padding: 2,
+		},
+	})
+}
+
+// Frame.Pack lays out children on each side of the frame.
+func TestDrawPacked(t *testing.T) {
+	frame := ui.NewFrame("Packed")
+	frame.Configure(ui.Config{
+		Width:      200,
+		Height:     100,
+		Background: testBackground,
+	})
+
+	frame.Pack(testLabel("North"), ui.Pack{Side: ui.N, PadY: 4})
+	frame.Pack(testLabel("West"), ui.Pack{Side: ui.W, PadX: 2})
+	frame.Pack(testLabel("East"), ui.Pack{Side: ui.E, PadX: 2})
+	frame.Pack(testLabel("South"), ui.Pack{Side: ui.S, FillX: true})
+
+	highlight := testLabel("Expand")
+	highlight.SetBackground(testHighlight)
+	frame.Pack(highlight, ui.Pack{Side: ui.N, Expand: true})
+
+	drawlog.Golden(t, frame, "packed")
+}
+
+// Tooltips are drawn beside their target with an arrow pointing to it.
+func TestDrawTooltip(t *testing.T) {
+	for _, test := range []struct {
+		Name string
+		Edge ui.Edge
+	}{
+		{"tooltip-right", ui.Right},
+		{"tooltip-bottom", ui.Bottom},
+	} {
+		target := ui.NewFrame("Target")
+		target.Configure(ui.Config{
+			Width:      40,
+			Height:     20,
+			Background: testBackground,
+		})
+		target.MoveTo(render.NewPoint(100, 100))
+
+		tt := ui.NewTooltip(target, ui.Tooltip{
+			Text: "Tooltip\ntext",
+			Edge: test.Edge,
+		})
+		tt.SetStyle(&style.Tooltip{
+			Background: testForeground,
+			Foreground: testHighlight,
+		})
+		tt.Show()
+
+		drawlog.Golden(t, target, test.Name)
+	}
+}
+
+// Frame.Grid lines up the labels and values of a form, and other widgets
+// may be placed over it.
+func TestDrawGrid(t *testing.T) {
+	frame := ui.NewFrame("Grid")
+	frame.Configure(ui.Config{
+		Width:      200,
+		Height:     100,
+		Background: testBackground,
+	})
+	frame.GridColumn(1, ui.GridTrack{Weight: 1})
+	frame.GridRow(2, ui.GridTrack{Weight: 1, MinSize: 10})
+
+	for i, name := range []string{"Name", "Difficulty"} {
+		value := ui.NewFrame("Value")
+		value.SetBackground(testHighlight)
+		value.Pack(testLabel("Value"), ui.Pack{Side: ui.W})
+		frame.Grid(testLabel(name), ui.Grid{Row: i, Sticky: ui.StickyW, PadX: 2})
+		frame.Grid(value, ui.Grid{Row: i, Column: 1, Sticky: ui.StickyEW, PadY: 2})
+	}
+	frame.Grid(testLabel("Spans both columns"), ui.Grid{Row: 2, ColumnSpan: 2})
+	frame.Place(testLabel("Placed"), ui.Place{Point: render.NewPoint(150, 80)})
+
+	drawlog.Golden(t, frame, "grid")
+}
+
+func TestDrawFlex(t *testing.T) {
+	frame := ui.NewFrame("Flex")
+	frame.Configure(ui.Config{
+		Width:      120,
+		Background: testBackground,
+	})
+	frame.SetFlexLayout(ui.FlexLayout{
+		Wrap:    true,
+		Justify: ui.JustifyCenter,
+		Align:   ui.AlignCenter,
+		Gap:     4,
+	})
+
+	for i, size := range []int{30, 40, 20, 50} {
+		swatch := ui.NewFrame("Swatch")
+		swatch.Configure(ui.Config{
+			Width:      size,
+			Height:     10 + i*4,
+			Background: testHighlight,
+		})
+		frame.Flex(swatch, ui.Flex{})
+	}
+	status := ui.NewFrame("Status")
+	status.SetBackground(testForeground)
+	status.Pack(testLabel("Grows"), ui.Pack{Side: ui.W})
+	frame.Flex(status, ui.Flex{Grow: 1})
+
+	drawlog.Golden(t, frame, "flex")
+}
@@ -0,0 +1,164 @@
+// Package drawlog is a render.Engine wrapper that records every draw call in
+// a stable text format, for snapshot tests of what widgets draw.
+//
+// Each call is one line, with its coordinates and colors:
+//
+//	box 0,0 120x24 #c0c0c0ff
+//	line 0,23 119,23 #808080ff
+//	text 6,4 "Click me!" size=12 color=#000000ff
+//
+// Compare the recording of a widget against a golden file with Golden.
+package drawlog
+
+import (
+	"fmt"
+	"image"
+	"strings"
+
+	"git.kirsle.net/go/render"
+	"git.kirsle.net/go/ui/softrender"
+)
+
+// Engine records the draw calls made to it, and passes them on to the
+// engine it wraps.
+type Engine struct {
+	render.Engine
+	lines    []string
+	textures map[render.Texturer]string
+}
+
+// Size of the window when New creates its own engine.
+var (
+	DefaultWidth  = 640
+	DefaultHeight = 480
+)
+
+// New wraps an engine to record its draw calls. If the engine is nil, a
+// headless softrender engine of the default size is used, so text can be
+// measured.
+func New(e render.Engine) *Engine {
+	if e == nil {
+		e = softrender.New(DefaultWidth, DefaultHeight)
+	}
+	return &Engine{
+		Engine:   e,
+		textures: map[render.Texturer]string{},
+	}
+}
+
+// Lines returns the recorded draw calls.
+func (e *Engine) Lines() []string {
+	return e.lines
+}
+
+// String returns the recorded draw calls, one per line.
+func (e *Engine) String() string {
+	if len(e.lines) == 0 {
+		return ""
+	}
+	return strings.Join(e.lines, "\n") + "\n"
+}
+
+// Reset forgets the recorded draw calls.
+func (e *Engine) Reset() {
+	e.lines = nil
+}
+
+// record a draw call.
+func (e *Engine) record(format string, v ...interface{}) {
+	e.lines = append(e.lines, fmt.Sprintf(format, v...))
+}
+
+// Clear records and clears the canvas.
+func (e *Engine) Clear(c render.Color) {
+	e.record("clear %s", formatColor(c))
+	e.Engine.Clear(c)
+}
+
+// DrawPoint records and draws a point.
+func (e *Engine) DrawPoint(c render.Color, p render.Point) {
+	e.record("point %s %s", formatPoint(p), formatColor(c))
+	e.Engine.DrawPoint(c, p)
+}
+
+// DrawLine records and draws a line.
+func (e *Engine) DrawLine(c render.Color, a, b render.Point) {
+	e.record("line %s %s %s", formatPoint(a), formatPoint(b), formatColor(c))
+	e.Engine.DrawLine(c, a, b)
+}
+
+// DrawRect records and draws the outline of a rectangle.
+func (e *Engine) DrawRect(c render.Color, r render.Rect) {
+	e.record("rect %s %s", formatRect(r), formatColor(c))
+	e.Engine.DrawRect(c, r)
+}
+
+// DrawBox records and draws a filled rectangle.
+func (e *Engine) DrawBox(c render.Color, r render.Rect) {
+	e.record("box %s %s", formatRect(r), formatColor(c))
+	e.Engine.DrawBox(c, r)
+}
+
+// DrawText records and draws a line of text.
+func (e *Engine) DrawText(text render.Text, p render.Point) error {
+	var attrs = []string{
+		fmt.Sprintf("size=%d", text.Size),
+		"color=" + formatColor(text.Color),
+	}
+	if text.Stroke != render.Invisible {
+		attrs = append(attrs, "stroke="+formatColor(text.Stroke))
+	}
+	if text.Shadow != render.Invisible {
+		attrs = append(attrs, "shadow="+formatColor(text.Shadow))
+	}
+	if text.FontFilename != "" {
+		attrs = append(attrs, fmt.Sprintf("font=%q", text.FontFilename))
+	}
+
+	e.record("text %s %q %s", formatPoint(p), text.Text, strings.Join(attrs, " "))
+	return e.Engine.DrawText(text, p)
+}
+
+// StoreTexture records and stores a texture.
+func (e *Engine) StoreTexture(name string, img image.Image) (render.Texturer, error) {
+	b := img.Bounds()
+	e.record("store %q %dx%d", name, b.Dx(), b.Dy())
+
+	tex, err := e.Engine.StoreTexture(name, img)
+	if err == nil {
+		e.textures[tex] = name
+	}
+	return tex, err
+}
+
+// LoadTexture loads a texture; it isn't a draw call, so it's not recorded.
+func (e *Engine) LoadTexture(name string) (render.Texturer, error) {
+	tex, err := e.Engine.LoadTexture(name)
+	if err == nil {
+		e.textures[tex] = name
+	}
+	return tex, err
+}
+
+// Copy records and draws a texture.
+func (e *Engine) Copy(t render.Texturer, src, dst render.Rect) {
+	name, ok := e.textures[t]
+	if !ok {
+		name = "?"
+	}
+	e.record("copy %q %s -> %s", name, formatRect(src), formatRect(dst))
+	e.Engine.Copy(t, src, dst)
+}
+
+// formatColor formats a color as #rrggbbaa.
+func formatColor(c render.Color) string {
+	return fmt.Sprintf("#%02x%02x%02x%02x", c.Red, c.Green, c.Blue, c.Alpha)
+}
+
+func formatPoint(p render.Point) string {
+	return fmt.Sprintf("%d,%d", p.X, p.Y)
+}
+
+func formatRect(r render.Rect) string {
+	return fmt.Sprintf("%d,%d %dx%d", r.X, r.Y, r.W, r.H)
+}
@@ -0,0 +1,107 @@
+package drawlog
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"git.kirsle.net/go/render"
+	"git.kirsle.net/go/ui"
+)
+
+// Update the golden files instead of comparing against them, with
+// `go test -update-golden`.
+var update = flag.Bool("update-golden", false, "update the drawlog golden files")
+
+// Record computes a widget and records what it draws when presented at
+// the point.
+func Record(w ui.Widget, p render.Point) *Engine {
+	e := New(nil)
+	w.Compute(e)
+	w.Present(e, p)
+	return e
+}
+
+// Golden records what a widget draws when presented at its own position,
+// and compares it against the golden file testdata/<name>.golden. With the
+// -update-golden flag, the file is written instead.
+func Golden(t testing.TB, w ui.Widget, name string) {
+	t.Helper()
+	AssertGolden(t, name, Record(w, w.Point()).String())
+}
+
+// AssertGolden compares a recording against the golden file
+// testdata/<name>.golden, or writes the file with the -update-golden flag.
+func AssertGolden(t testing.TB, name, got string) {
+	t.Helper()
+
+	filename := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filename, []byte(got), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("%s (run `go test -update-golden` to create it)", err)
+	}
+
+	if diff := Diff(string(want), got); diff != "" {
+		t.Errorf("%s does not match what was drawn (run `go test -update-golden` to update it):\n%s", filename, diff)
+	}
+}
+
+// Diff compares two recordings line by line. Returns the lines that differ,
+// with "-" for the expected line and "+" for the line that was drawn, or an
+// empty string if they are the same. The lines in common are matched up
+// first (a longest common subsequence), so one extra or missing draw call
+// shows as that one line rather than as every line after it.
+func Diff(want, got string) string {
+	var (
+		a    = strings.Split(strings.TrimSuffix(want, "\n"), "\n")
+		b    = strings.Split(strings.TrimSuffix(got, "\n"), "\n")
+		lcs  = make([][]int, len(a)+1)
+		diff = []string{}
+	)
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:]
+	// and b[j:].
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	for i, j := 0, 0; i < len(a) || j < len(b); {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			i++
+			j++
+		case j == len(b) || (i < len(a) && lcs[i+1][j] >= lcs[i][j+1]):
+			diff = append(diff, fmt.Sprintf("%4d - %s", i+1, a[i]))
+			i++
+		default:
+			diff = append(diff, fmt.Sprintf("%4d + %s", j+1, b[j]))
+			j++
+		}
+	}
+
+	return strings.Join(diff, "\n")
+}
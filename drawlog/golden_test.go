@@ -0,0 +1,24 @@
+package drawlog
+
+import "testing"
+
+func TestDiff(t *testing.T) {
+	var tests = []struct {
+		Want   string
+		Got    string
+		Expect string
+	}{
+		{"a\nb\nc\n", "a\nb\nc\n", ""},
+		{"a\nb\nc\n", "a\nB\nc\n", "   2 - b\n   2 + B"},
+
+		// An extra or missing line doesn't shift the lines after it.
+		{"a\nb\nc\nd\n", "a\nx\nb\nc\nd\n", "   2 + x"},
+		{"a\nb\nc\nd\n", "a\nc\nd\n", "   2 - b"},
+		{"a\nb\n", "a\nb\nc\n", "   3 + c"},
+	}
+	for _, test := range tests {
+		if diff := Diff(test.Want, test.Got); diff != test.Expect {
+			t.Errorf("Diff(%q, %q): expected\n%s\ngot\n%s", test.Want, test.Got, test.Expect, diff)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package ui_test
+
+import (
+	"fmt"
+	"testing"
+
+	"git.kirsle.net/go/render"
+	"git.kirsle.net/go/ui"
+	"git.kirsle.net/go/ui/softrender"
+)
+
+// A wrapping palette of swatches flows again when its Frame is resized.
+func TestFlexWrap(t *testing.T) {
+	var (
+		engine  = softrender.New(200, 200)
+		palette = ui.NewFrame("Palette")
+		colors  = make([]*ui.Frame, 6)
+	)
+	palette.Resize(render.NewRect(100, 0))
+	palette.SetFlexLayout(ui.FlexLayout{Wrap: true, Gap: 4})
+	for i := range colors {
+		colors[i] = ui.NewFrame("Color")
+		colors[i].Resize(render.NewRect(30, 30))
+		palette.Flex(colors[i], ui.Flex{})
+	}
+
+	// points returns where the swatches are.
+	points := func() string {
+		var s string
+		for _, c := range colors {
+			s += fmt.Sprintf(" %d,%d", c.Point().X, c.Point().Y)
+		}
+		return s
+	}
+
+	palette.Compute(engine)
+	if p := points(); p != " 0,0 34,0 68,0 0,34 34,34 68,34" || palette.Size().H != 64 {
+		t.Errorf("expected 2 lines of 3 swatches, 64px tall, got%s and %dpx", p, palette.Size().H)
+	}
+
+	palette.ResizeAuto(render.NewRect(200, palette.Size().H))
+	palette.Compute(engine)
+	if p := points(); p != " 0,0 34,0 68,0 102,0 136,0 170,0" || palette.Size().H != 30 {
+		t.Errorf("expected 1 line of 6 swatches, 30px tall, got%s and %dpx", p, palette.Size().H)
+	}
+}
+
+// Widgets grow into the free space of their line, or else are spread by
+// the Justify setting.
+func TestFlexGrow(t *testing.T) {
+	var (
+		engine  = softrender.New(200, 200)
+		toolbar = ui.NewFrame("Toolbar")
+		buttons = make([]*ui.Frame, 3)
+	)
+	toolbar.Resize(render.NewRect(200, 0))
+	for i := range buttons {
+		buttons[i] = ui.NewFrame("Button")
+		buttons[i].Resize(render.NewRect(20, 10+i*5))
+		toolbar.Flex(buttons[i], ui.Flex{})
+	}
+
+	for _, test := range []struct {
+		layout ui.FlexLayout
+		grow   int
+		expect string
+	}{
+		{ui.FlexLayout{}, 1, "0,0 20x10 20,0 160x15 180,0 20x20"},
+		{ui.FlexLayout{Gap: 10, Align: ui.AlignStretch}, 1, "0,0 20x20 30,0 140x20 180,0 20x20"},
+		{ui.FlexLayout{Justify: ui.JustifyEnd, Align: ui.AlignEnd}, 0, "140,10 20x10 160,5 20x15 180,0 20x20"},
+		{ui.FlexLayout{Justify: ui.JustifySpaceBetween, Align: ui.AlignCenter}, 0, "0,5 20x10 90,2 20x15 180,0 20x20"},
+		{ui.FlexLayout{Direction: ui.FlexColumn, Justify: ui.JustifyCenter}, 0, "0,0 20x10 0,10 20x15 0,25 20x20"},
+	} {
+		toolbar.SetFlexLayout(test.layout)
+		toolbar.Flex(buttons[1], ui.Flex{Grow: test.grow})
+		toolbar.Compute(engine)
+
+		var got []string
+		for _, b := range buttons {
+			got = append(got, fmt.Sprintf("%d,%d %dx%d", b.Point().X, b.Point().Y, b.Size().W, b.Size().H))
+		}
+		if fmt.Sprint(got) != "["+test.expect+"]" {
+			t.Errorf("%+v: expected %s, got %s", test.layout, test.expect, got)
+		}
+	}
+}
@@ -0,0 +1,286 @@
+package ui
+
+import "errors"
+
+/*
+focus.go holds the Supervisor methods related to keyboard focus: which widget
+currently holds the focus, and moving it around with Tab and Shift+Tab.
+*/
+
+// Error messages related to keyboard focus.
+var (
+	ErrNotFocusable      = errors.New("widget is not focusable")
+	ErrFocusOutsideScope = errors.New("widget is outside of the current focus scope")
+)
+
+// Focuser is a widget that can receive the keyboard focus. The Supervisor
+// manages which widget has the focus; widgets only need to declare whether
+// they can receive it. BaseWidget implements it, so a widget that doesn't
+// embed a BaseWidget is never focused unless it implements Focuser itself.
+type Focuser interface {
+	Focusable() bool   // Whether the widget can receive keyboard focus
+	SetFocusable(bool) //
+	HasFocus() bool    // Whether the widget currently has keyboard focus
+	SetHasFocus(bool)  // called by the Supervisor
+}
+
+// isFocusable returns whether a widget can receive the keyboard focus.
+func isFocusable(w Widget) bool {
+	f, ok := w.(Focuser)
+	return ok && f.Focusable()
+}
+
+// hasFocus returns whether a widget has the keyboard focus.
+func hasFocus(w Widget) bool {
+	f, ok := w.(Focuser)
+	return ok && f.HasFocus()
+}
+
+// setHasFocus sets the focus state of a widget, if it has one.
+func setHasFocus(w Widget, v bool) {
+	if f, ok := w.(Focuser); ok {
+		f.SetHasFocus(v)
+	}
+}
+
+// FocusedWidget returns the widget that currently holds the keyboard focus,
+// or nil if no widget is focused.
+func (s *Supervisor) FocusedWidget() Widget {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.focused
+}
+
+// IsFocused returns whether the widget currently holds the keyboard focus.
+func (s *Supervisor) IsFocused(w Widget) bool {
+	focused := s.FocusedWidget()
+	return focused != nil && focused == w
+}
+
+// Focus gives the keyboard focus to a widget.
+//
+// The widget must be focusable (see Focuser) and be inside the
+// current focus scope: if a modal is active, the widget must belong to the
+// top-most modal, or if a managed Window is focused and the widget is part
+// of a managed Window, it must belong to the focused one.
+//
+// The previously focused widget receives a FocusOut event and the new widget
+// receives a FocusIn event.
+func (s *Supervisor) Focus(w Widget) error {
+	if w == nil {
+		s.Blur()
+		return nil
+	}
+
+	if !isFocusable(w) {
+		return ErrNotFocusable
+	}
+
+	if !s.inFocusScope(w) {
+		return ErrFocusOutsideScope
+	}
+
+	s.lock.Lock()
+	var old = s.focused
+	if old == w {
+		s.lock.Unlock()
+		return nil
+	}
+	s.focused = w
+	s.lock.Unlock()
+
+	if old != nil {
+		s.sendBlur(old)
+	}
+	setHasFocus(w, true)
+	w.Event(FocusIn, EventData{
+		Supervisor: s,
+		Widget:     w,
+	})
+	return nil
+}
+
+// Blur removes the keyboard focus from whichever widget currently holds it.
+func (s *Supervisor) Blur() {
+	s.blur()
+}
+
+// blur removes the keyboard focus and returns the widget that held it, or
+// nil.
+func (s *Supervisor) blur() Widget {
+	s.lock.Lock()
+	var old = s.focused
+	s.focused = nil
+	s.lock.Unlock()
+
+	if old != nil {
+		s.sendBlur(old)
+	}
+	return old
+}
+
+// sendBlur tells a widget that it lost the keyboard focus.
+func (s *Supervisor) sendBlur(old Widget) {
+	setHasFocus(old, false)
+	old.Event(FocusOut, EventData{
+		Supervisor: s,
+		Widget:     old,
+	})
+}
+
+// FocusNext moves the keyboard focus to the next focusable widget in tree
+// order, wrapping around at the end. Returns the newly focused widget, or
+// nil if there are no focusable widgets in scope.
+func (s *Supervisor) FocusNext() Widget {
+	return s.focusStep(1)
+}
+
+// FocusPrev moves the keyboard focus to the previous focusable widget in tree
+// order, wrapping around at the beginning.
+func (s *Supervisor) FocusPrev() Widget {
+	return s.focusStep(-1)
+}
+
+// focusStep moves the focus forward (+1) or backward (-1) in the focus chain.
+func (s *Supervisor) focusStep(direction int) Widget {
+	chain := s.FocusChain()
+	if len(chain) == 0 {
+		s.Blur()
+		return nil
+	}
+
+	// Find the current widget in the chain. If it's not there (or nothing
+	// is focused), start from the first or last widget.
+	var (
+		index   = -1
+		focused = s.FocusedWidget()
+	)
+	for i, w := range chain {
+		if w == focused {
+			index = i
+			break
+		}
+	}
+
+	if index == -1 {
+		if direction > 0 {
+			index = 0
+		} else {
+			index = len(chain) - 1
+		}
+	} else {
+		index = (index + direction + len(chain)) % len(chain)
+	}
+
+	s.Focus(chain[index])
+	return chain[index]
+}
+
+// FocusChain returns the focusable widgets in the current focus scope in
+// tree order, which is the order that Tab will move the focus through.
+//
+// The scope is the top-most modal if one is active; otherwise the focused
+// managed Window if there is one visible; otherwise all supervised widgets
+// that are not part of a managed Window.
+func (s *Supervisor) FocusChain() []Widget {
+	var (
+		roots      []Widget
+		chain      = []Widget{}
+		supervised = map[Widget]interface{}{}
+		widgets    = s.slots()
+	)
+
+	for _, child := range widgets {
+		supervised[child.widget] = nil
+	}
+
+	if scope := s.focusScope(); scope != nil {
+		roots = []Widget{scope}
+	} else {
+		// Find the distinct top-level ancestors of all supervised widgets, in
+		// the order they were added, skipping managed windows.
+		var seen = map[Widget]interface{}{}
+		for _, child := range widgets {
+			root := rootWidget(child.widget)
+			if _, ok := seen[root]; ok {
+				continue
+			}
+			seen[root] = nil
+
+			if window, ok := root.(*Window); ok && window.managed {
+				continue
+			}
+			roots = append(roots, root)
+		}
+	}
+
+	var crawl func(Widget)
+	crawl = func(node Widget) {
+		if node.Hidden() {
+			return
+		}
+
+		if _, ok := supervised[node]; ok && isFocusable(node) {
+			chain = append(chain, node)
+		}
+
+		for _, child := range node.Children() {
+			crawl(child)
+		}
+	}
+
+	for _, root := range roots {
+		crawl(root)
+	}
+
+	return chain
+}
+
+// focusScope returns the widget that keyboard focus is confined to: the
+// top-most modal, or else the focused managed Window. Returns nil if focus
+// may go to any supervised widget outside of managed windows.
+func (s *Supervisor) focusScope() Widget {
+	if modal := s.GetModal(); modal != nil {
+		return modal
+	}
+
+	if s.winFocus != nil && !s.winFocus.window.Hidden() {
+		return s.winFocus.window
+	}
+
+	return nil
+}
+
+// inFocusScope returns whether a widget may receive the keyboard focus given
+// the current modal and window manager state.
+func (s *Supervisor) inFocusScope(w Widget) bool {
+	if modal := s.GetModal(); modal != nil {
+		return w == modal || HasParent(w, modal)
+	}
+
+	isManaged, isFocused := widgetInFocusedWindow(w)
+	return !isManaged || isFocused
+}
+
+// loopFocus is a subroutine of Supervisor.Loop that validates the keyboard
+// focus. The Tab and Shift+Tab keys are handled in loopKeys.
+func (s *Supervisor) loopFocus() {
+	// If the focused widget has been hidden or fell outside the focus scope
+	// (e.g. another window was raised), drop the focus.
+	if focused := s.FocusedWidget(); focused != nil && (focused.Hidden() || !s.inFocusScope(focused)) {
+		s.Blur()
+	}
+}
+
+// rootWidget returns the top-most ancestor of a widget (or the widget itself
+// if it has no parent).
+func rootWidget(w Widget) Widget {
+	var node = w
+	for {
+		parent, ok := node.Parent()
+		if !ok || parent == nil {
+			return node
+		}
+		node = parent
+	}
+}
@@ -15,7 +15,10 @@ type Frame struct {
 	// Widget placement settings.
 	packs   map[Side][]*packedWidget // Packed widgets
 	placed  []*placedWidget          // Placed widgets
+	grid    gridLayout               // Gridded widgets
+	flex    flexState                // Flexed widgets
 	widgets []Widget
+	clipped bool // clip the children to the frame
 }
 
 // NewFrame creates a new Frame.
@@ -59,6 +62,7 @@ func (w *Frame) Add(child Widget) error {
 		}
 	}
 	w.widgets = append(w.widgets, child)
+	w.Invalidate()
 	return nil
 }
 
@@ -70,12 +74,47 @@ func (w *Frame) Children() []Widget {
 // Compute the size of the Frame.
 func (w *Frame) Compute(e render.Engine) {
 	w.computePacked(e)
+	w.computeGrid(e)
 	w.computePlaced(e)
 
 	// Call the BaseWidget Compute in case we have subscribers.
 	w.BaseWidget.Compute(e)
 }
 
+// computeChild computes a child widget, unless it is clean: unchanged since
+// the last frame was drawn (see Supervisor.NeedsRedraw), so its size is too.
+// Widgets with Compute handlers are computed anyway.
+func computeChild(child Widget, e render.Engine) {
+	if isDirty(child) || handlesEvent(child, Compute) {
+		child.Compute(e)
+	}
+}
+
+// SetClipped sets whether the Frame clips its children, so that the parts
+// of them outside the frame's border aren't drawn, nor hit by the mouse.
+// The ScrollFrame clips its content this way.
+func (w *Frame) SetClipped(v bool) {
+	if w.clipped != v {
+		w.clipped = v
+		w.Invalidate()
+		w.geometryChanged()
+	}
+}
+
+// Clipped returns whether the Frame clips its children.
+func (w *Frame) Clipped() bool {
+	return w.clipped
+}
+
+// clipRect returns the absolute rect a clipped Frame's children are clipped
+// to, for hit-testing.
+func (w *Frame) clipRect() (render.Rect, bool) {
+	if !w.clipped {
+		return render.Rect{}, false
+	}
+	return AbsoluteRect(w), true
+}
+
 // Present the Frame.
 func (w *Frame) Present(e render.Engine, P render.Point) {
 	if w.Hidden() {
@@ -97,6 +136,24 @@ func (w *Frame) Present(e render.Engine, P render.Point) {
 		H: S.H - w.BoxThickness(2),
 	})
 
+	// Clip the children inside the border. An engine clipped already, e.g.
+	// to the area of a window that changed, skips the children outside of
+	// it too.
+	var (
+		engine  = e
+		clip, _ = e.(*ClipEngine)
+	)
+	if w.clipped {
+		clip = Clip(e)
+		clip.PushClip(render.Rect{
+			X: P.X + w.BoxThickness(1),
+			Y: P.Y + w.BoxThickness(1),
+			W: S.W - w.BoxThickness(2),
+			H: S.H - w.BoxThickness(2),
+		})
+		engine = clip
+	}
+
 	// Draw the widgets.
 	for _, child := range w.widgets {
 		if child.Hidden() {
@@ -108,7 +165,19 @@ func (w *Frame) Present(e render.Engine, P render.Point) {
 			P.X+p.X+w.BoxThickness(1),
 			P.Y+p.Y+w.BoxThickness(1),
 		)
-		child.Present(e, moveTo)
+
+		// Skip the children scrolled out of view.
+		if clip != nil {
+			size := child.Size()
+			if !clip.Visible(render.Rect{X: moveTo.X, Y: moveTo.Y, W: size.W, H: size.H}) {
+				continue
+			}
+		}
+		child.Present(engine, moveTo)
+	}
+
+	if w.clipped {
+		clip.PopClip()
 	}
 
 	// Call the BaseWidget Present in case we have subscribers.
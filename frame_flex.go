@@ -0,0 +1,442 @@
+package ui
+
+import (
+	"git.kirsle.net/go/render"
+)
+
+// FlexLayout provides configuration fields for Frame.SetFlexLayout(), which
+// lays out the Frame's flexed widgets in a row or a column like a CSS
+// flexbox.
+type FlexLayout struct {
+	// Direction of the main axis the widgets flow along, FlexRow (left to
+	// right) by default or FlexColumn (top to bottom).
+	Direction FlexDirection
+
+	// Wrap the widgets onto new lines when the Frame is too narrow (or too
+	// short, in a column) for all of them.
+	Wrap bool
+
+	// Justify the widgets along the main axis: how to use the free space of
+	// a line that no widget grows into.
+	Justify FlexJustify
+
+	// Align the widgets across the main axis, within their line.
+	Align FlexAlign
+
+	// Gap in pixels between the widgets of a line, and between the lines.
+	Gap int
+}
+
+// Flex provides configuration fields for Frame.Flex().
+type Flex struct {
+	// Grow shares the free space of a line between its widgets: a widget of
+	// Grow 2 takes twice as much as one of Grow 1. Widgets of Grow 0 keep
+	// their size.
+	Grow int
+
+	// Shrink shares the space missing from a line that doesn't wrap, in
+	// proportion to the widgets' sizes. Widgets of Shrink 0 don't shrink.
+	Shrink int
+
+	// Basis is the size of the widget along the main axis before growing or
+	// shrinking. Zero means the widget's own size.
+	Basis int
+}
+
+// FlexDirection is the main axis of a FlexLayout.
+type FlexDirection uint8
+
+// FlexDirection values.
+const (
+	FlexRow FlexDirection = iota
+	FlexColumn
+)
+
+// FlexJustify is how the widgets of a FlexLayout are spread along a line.
+type FlexJustify uint8
+
+// FlexJustify values.
+const (
+	JustifyStart        FlexJustify = iota // packed at the start of the line
+	JustifyEnd                             // packed at the end of the line
+	JustifyCenter                          // packed in the middle of the line
+	JustifySpaceBetween                    // the first and last at the edges, equal space between
+	JustifySpaceAround                     // equal space around each widget
+	JustifySpaceEvenly                     // equal space between the widgets and the edges
+)
+
+// FlexAlign is how the widgets of a FlexLayout are placed across a line.
+type FlexAlign uint8
+
+// FlexAlign values.
+const (
+	AlignStart   FlexAlign = iota // at the top of the line (or left of a column)
+	AlignEnd                      // at the bottom of the line
+	AlignCenter                   // centered in the line
+	AlignStretch                  // stretched to the size of the line
+)
+
+// flexedWidget holds the data for a flexed widget in the frame. The layout
+// remembers the size it gave the widget, so a widget it grew or stretched
+// is measured at its natural size again on the next Compute.
+type flexedWidget struct {
+	widget   Widget
+	flex     Flex
+	natural  render.Rect
+	assigned render.Rect
+}
+
+// flexState holds the flex layout and its widgets.
+type flexState struct {
+	layout  FlexLayout
+	widgets []*flexedWidget
+	cross   int // size across the main axis the layout gave the Frame
+}
+
+// flexItem is a flexed widget during a layout, with its sizes along the
+// main axis and across it.
+type flexItem struct {
+	fw    *flexedWidget
+	main  int
+	cross int
+}
+
+// SetFlexLayout sets how the Frame lays out its flexed widgets, see Flex.
+func (w *Frame) SetFlexLayout(v FlexLayout) {
+	if w.flex.layout != v {
+		w.flex.layout = v
+		w.Invalidate()
+	}
+}
+
+// FlexLayout returns how the Frame lays out its flexed widgets.
+func (w *Frame) FlexLayout() FlexLayout {
+	return w.flex.layout
+}
+
+// Flex adds a widget to the frame's flex layout (see SetFlexLayout): the
+// widgets flow along a row or column in the order they were added, wrapping
+// onto new lines if the layout Wraps. You may call Flex on a widget multiple
+// times to update its configuration.
+//
+// The lines of widgets fill the inside of the Frame from its top left corner
+// and the Frame grows or shrinks across them to fit its lines, while along
+// the main axis it keeps the size it was given, or else the size of its
+// widgets on a single line. When the Frame is resized, e.g. by packing it to
+// Fill its parent, the widgets flow again to its new size. Like with Grid,
+// widgets packed into the same Frame overlap the flexed ones.
+func (w *Frame) Flex(child Widget, config Flex) {
+	// Update an already flexed widget.
+	for _, current := range w.flex.widgets {
+		if current.widget == child {
+			if current.flex != config {
+				current.flex = config
+				w.Invalidate()
+			}
+			return
+		}
+	}
+
+	w.flex.widgets = append(w.flex.widgets, &flexedWidget{
+		widget: child,
+		flex:   config,
+	})
+	w.Add(child)
+
+	// Adopt the child widget so it can access the Frame.
+	child.SetParent(w)
+}
+
+// Unflex removes a widget from the flex layout and the frame. Returns false
+// if the widget wasn't in the flex layout.
+func (w *Frame) Unflex(child Widget) bool {
+	for i, current := range w.flex.widgets {
+		if current.widget != child {
+			continue
+		}
+
+		w.flex.widgets = append(w.flex.widgets[:i], w.flex.widgets[i+1:]...)
+		for j, widget := range w.widgets {
+			if widget == child {
+				w.widgets = append(w.widgets[:j], w.widgets[j+1:]...)
+				break
+			}
+		}
+		w.Invalidate()
+		return true
+	}
+	return false
+}
+
+// computeFlex lays out the flexed widgets in the Frame's current size, and
+// returns the size they take. It is called by computePacked, which sizes
+// the Frame to fit them (see flexFrameSize).
+func (w *Frame) computeFlex(e render.Engine) render.Rect {
+	var (
+		L     = w.flex.layout
+		row   = L.Direction == FlexRow
+		S     = w.Size()
+		avail = S.H - w.BoxThickness(2)
+		items = []*flexItem{}
+	)
+	if row {
+		avail = S.W - w.BoxThickness(2)
+	}
+	if !w.FixedSize() || avail <= 0 {
+		avail = -1 // no limit: all on one line
+	}
+
+	// Converters between main/cross sizes and widths/heights.
+	var (
+		mainOf = func(r render.Rect) int {
+			if row {
+				return r.W
+			}
+			return r.H
+		}
+		crossOf = func(r render.Rect) int {
+			if row {
+				return r.H
+			}
+			return r.W
+		}
+		rect = func(main, cross int) render.Rect {
+			if row {
+				return render.NewRect(main, cross)
+			}
+			return render.NewRect(cross, main)
+		}
+		point = func(main, cross int) render.Point {
+			if row {
+				return render.NewPoint(main, cross)
+			}
+			return render.NewPoint(cross, main)
+		}
+	)
+
+	// Measure the widgets at their natural sizes.
+	for _, fw := range w.flex.widgets {
+		computeChild(fw.widget, e)
+		if fw.widget.Hidden() {
+			continue
+		}
+
+		size := fw.widget.Size()
+		if size == fw.assigned && fw.assigned != fw.natural {
+			size = fw.natural
+		} else {
+			fw.natural = size
+		}
+
+		item := &flexItem{
+			fw:    fw,
+			main:  mainOf(size),
+			cross: crossOf(size),
+		}
+		if fw.flex.Basis > 0 {
+			item.main = fw.flex.Basis
+		}
+		items = append(items, item)
+	}
+
+	// Break the widgets into lines.
+	var (
+		lines = [][]*flexItem{}
+		line  = []*flexItem{}
+		used  int
+	)
+	for _, item := range items {
+		if L.Wrap && avail >= 0 && len(line) > 0 && used+L.Gap+item.main > avail {
+			lines = append(lines, line)
+			line, used = []*flexItem{}, 0
+		}
+		if len(line) > 0 {
+			used += L.Gap
+		}
+		used += item.main
+		line = append(line, item)
+	}
+	if len(line) > 0 {
+		lines = append(lines, line)
+	}
+
+	// Size and place the widgets of each line.
+	var (
+		content render.Rect
+		offset  int // of the line across the main axis
+	)
+	for _, line := range lines {
+		var (
+			n         = len(line)
+			free      int
+			lineCross int
+		)
+		if avail >= 0 {
+			free = avail - flexLineSize(line, L.Gap)
+		}
+		for _, item := range line {
+			lineCross = maxInt(lineCross, item.cross)
+		}
+
+		if free > 0 {
+			free -= growFlexLine(line, free)
+		} else if free < 0 {
+			free += shrinkFlexLine(line, -free)
+		}
+
+		// Spread the free space left.
+		var (
+			pos     int
+			between = L.Gap
+		)
+		if free > 0 {
+			switch L.Justify {
+			case JustifyEnd:
+				pos = free
+			case JustifyCenter:
+				pos = free / 2
+			case JustifySpaceBetween:
+				if n > 1 {
+					between += free / (n - 1)
+				}
+			case JustifySpaceAround:
+				pos = free / (n * 2)
+				between += free / n
+			case JustifySpaceEvenly:
+				pos = free / (n + 1)
+				between += free / (n + 1)
+			}
+		}
+
+		for i, item := range line {
+			var (
+				cross    = item.cross
+				crossPos int
+			)
+			switch L.Align {
+			case AlignEnd:
+				crossPos = lineCross - cross
+			case AlignCenter:
+				crossPos = (lineCross - cross) / 2
+			case AlignStretch:
+				cross = lineCross
+			}
+
+			// Resize the widget if it grew, shrank or stretched.
+			var (
+				fw     = item.fw
+				resize = rect(item.main, cross)
+			)
+			if resize != fw.widget.Size() {
+				fw.widget.ResizeAuto(resize)
+				computeChild(fw.widget, e)
+			}
+			fw.assigned = fw.widget.Size()
+
+			fw.widget.MoveTo(point(pos, offset+crossPos))
+			pos += item.main
+			if i < n-1 {
+				pos += between
+			}
+		}
+
+		content = rect(maxInt(mainOf(content), pos), offset+lineCross)
+		offset += lineCross + L.Gap
+	}
+
+	return content
+}
+
+// flexLineSize returns the size of a line of widgets along the main axis.
+func flexLineSize(line []*flexItem, gap int) int {
+	var size = gap * (len(line) - 1)
+	for _, item := range line {
+		size += item.main
+	}
+	return size
+}
+
+// growFlexLine shares free space between the widgets of a line by their
+// Grow, and returns how much of it they took.
+func growFlexLine(line []*flexItem, free int) int {
+	var total int
+	for _, item := range line {
+		total += item.fw.flex.Grow
+	}
+	if total == 0 {
+		return 0
+	}
+
+	var given int
+	for _, item := range line {
+		if grow := item.fw.flex.Grow; grow > 0 {
+			share := free * grow / total
+			item.main += share
+			given += share
+		}
+	}
+
+	// The rounding remainder goes to the last growing widget.
+	for i := len(line) - 1; i >= 0; i-- {
+		if line[i].fw.flex.Grow > 0 {
+			line[i].main += free - given
+			break
+		}
+	}
+	return free
+}
+
+// shrinkFlexLine takes space missing from a line from its widgets by their
+// Shrink and size, and returns how much they gave up.
+func shrinkFlexLine(line []*flexItem, missing int) int {
+	var total int
+	for _, item := range line {
+		total += item.fw.flex.Shrink * item.main
+	}
+	if total == 0 {
+		return 0
+	}
+
+	var taken int
+	for _, item := range line {
+		share := missing * item.fw.flex.Shrink * item.main / total
+		if share > item.main {
+			share = item.main
+		}
+		item.main -= share
+		taken += share
+	}
+	return taken
+}
+
+// flexFrameSize returns the size computePacked gives a Frame with flexed
+// widgets, from the size it computed (with the BoxThickness on each side)
+// and the size of the flexed widgets. Across the main axis, the Frame fits
+// its lines: it grows to fit them and shrinks back when they take less room,
+// unless it was given a larger size elsewhere, e.g. by Configure or by a
+// parent that Fills it.
+func (w *Frame) flexFrameSize(frameSize, content render.Rect) render.Rect {
+	var (
+		row   = w.flex.layout.Direction == FlexRow
+		S     = w.Size()
+		size  = S.W
+		cross = content.W + w.BoxThickness(2)
+	)
+	if row {
+		size, cross = S.H, content.H+w.BoxThickness(2)
+	}
+
+	// Keep a larger size from elsewhere.
+	if size != 0 && size != w.flex.cross && size > cross {
+		cross = size
+	} else {
+		w.flex.cross = cross
+	}
+
+	if row {
+		frameSize.H = cross + w.BoxThickness(2)
+	} else {
+		frameSize.W = cross + w.BoxThickness(2)
+	}
+	return frameSize
+}
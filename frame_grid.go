@@ -0,0 +1,335 @@
+package ui
+
+import (
+	"git.kirsle.net/go/render"
+)
+
+// Grid provides configuration fields for Frame.Grid().
+type Grid struct {
+	// Row and Column of the cell, from zero at the top left corner. The
+	// widget may span more than one row or column.
+	Row        int
+	Column     int
+	RowSpan    int // default 1
+	ColumnSpan int // default 1
+
+	// Sides of the cell the widget sticks to. It is centered in its cell by
+	// default, and stretches to fill the cell if it sticks to opposite sides.
+	Sticky Sticky
+
+	// Padding around the widget inside its cell.
+	PadX int
+	PadY int
+}
+
+// Sticky sets the sides of a grid cell that a widget sticks to. Combine
+// them like StickyN | StickyW.
+type Sticky uint8
+
+// Sticky values.
+const (
+	StickyN Sticky = 1 << iota
+	StickyE
+	StickyS
+	StickyW
+
+	StickyNS  = StickyN | StickyS
+	StickyEW  = StickyE | StickyW
+	StickyAll = StickyNS | StickyEW
+)
+
+// GridTrack configures a row or column of the grid, see Frame.GridRow and
+// Frame.GridColumn.
+type GridTrack struct {
+	// Weight shares the frame's extra space between the rows (or columns):
+	// a track of weight 2 grows twice as much as one of weight 1. Tracks of
+	// weight 0 keep the size of their widgets.
+	Weight int
+
+	// MinSize is the least width (or height) of the track in pixels, even
+	// when it is empty.
+	MinSize int
+}
+
+// griddedWidget holds the data for a widget in the frame's grid.
+type griddedWidget struct {
+	widget   Widget
+	grid     Grid
+	natural  render.Rect // its own size, before the grid stretched it
+	assigned render.Rect // the size the grid gave it
+}
+
+// gridLayout holds the grid settings and the sizes of its tracks, as
+// measured by the last Compute.
+type gridLayout struct {
+	widgets []*griddedWidget
+	rows    map[int]GridTrack
+	columns map[int]GridTrack
+	heights []int
+	widths  []int
+}
+
+// Grid puts a widget into a cell of the frame's grid. Each column is as wide
+// as its widest widget and each row as tall as its tallest one; the frame's
+// extra space goes to the rows and columns with a Weight (see GridRow and
+// GridColumn). You may call Grid on a widget multiple times to update its
+// configuration.
+//
+// The grid fills the inside of the Frame from its top left corner. Widgets
+// may be packed and placed into the same Frame, which grows to fit both its
+// grid and its packed widgets, but they overlap the grid: to pack a grid
+// beside other widgets, Grid its widgets into a Frame of its own.
+func (w *Frame) Grid(child Widget, config Grid) {
+	if config.Row < 0 {
+		config.Row = 0
+	}
+	if config.Column < 0 {
+		config.Column = 0
+	}
+	if config.RowSpan < 1 {
+		config.RowSpan = 1
+	}
+	if config.ColumnSpan < 1 {
+		config.ColumnSpan = 1
+	}
+
+	// Update an already gridded widget.
+	for _, current := range w.grid.widgets {
+		if current.widget == child {
+			if current.grid != config {
+				current.grid = config
+				w.Invalidate()
+			}
+			return
+		}
+	}
+
+	w.grid.widgets = append(w.grid.widgets, &griddedWidget{
+		widget: child,
+		grid:   config,
+	})
+	w.Add(child)
+
+	// Adopt the child widget so it can access the Frame.
+	child.SetParent(w)
+}
+
+// Ungrid removes a widget from the grid and the frame. Returns false if the
+// widget wasn't in the grid.
+func (w *Frame) Ungrid(child Widget) bool {
+	for i, current := range w.grid.widgets {
+		if current.widget != child {
+			continue
+		}
+
+		w.grid.widgets = append(w.grid.widgets[:i], w.grid.widgets[i+1:]...)
+		for j, widget := range w.widgets {
+			if widget == child {
+				w.widgets = append(w.widgets[:j], w.widgets[j+1:]...)
+				break
+			}
+		}
+		w.Invalidate()
+		return true
+	}
+	return false
+}
+
+// GridRow configures the weight and minimum size of a row of the grid.
+func (w *Frame) GridRow(row int, config GridTrack) {
+	if w.grid.rows == nil {
+		w.grid.rows = map[int]GridTrack{}
+	}
+	if w.grid.rows[row] != config {
+		w.grid.rows[row] = config
+		w.Invalidate()
+	}
+}
+
+// GridColumn configures the weight and minimum size of a column of the grid.
+func (w *Frame) GridColumn(column int, config GridTrack) {
+	if w.grid.columns == nil {
+		w.grid.columns = map[int]GridTrack{}
+	}
+	if w.grid.columns[column] != config {
+		w.grid.columns[column] = config
+		w.Invalidate()
+	}
+}
+
+// measureGrid computes the gridded widgets and the natural sizes of the
+// rows and columns, and returns the size of the whole grid.
+func (w *Frame) measureGrid(e render.Engine) render.Rect {
+	var (
+		rows    int
+		columns int
+	)
+	for _, row := range w.grid.widgets {
+		computeChild(row.widget, e)
+
+		// Measure the widget at its natural size, not as it was stretched.
+		if size := row.widget.Size(); size != row.assigned || row.assigned == row.natural {
+			row.natural = size
+		}
+
+		rows = maxInt(rows, row.grid.Row+row.grid.RowSpan)
+		columns = maxInt(columns, row.grid.Column+row.grid.ColumnSpan)
+	}
+	for row := range w.grid.rows {
+		rows = maxInt(rows, row+1)
+	}
+	for column := range w.grid.columns {
+		columns = maxInt(columns, column+1)
+	}
+
+	w.grid.heights = measureTracks(w.grid.widgets, rows, w.grid.rows, func(g Grid, size render.Rect) (int, int, int) {
+		return g.Row, g.RowSpan, size.H + g.PadY*2
+	})
+	w.grid.widths = measureTracks(w.grid.widgets, columns, w.grid.columns, func(g Grid, size render.Rect) (int, int, int) {
+		return g.Column, g.ColumnSpan, size.W + g.PadX*2
+	})
+	return render.NewRect(sumInts(w.grid.widths), sumInts(w.grid.heights))
+}
+
+// measureTracks returns the natural sizes of the rows or columns of the
+// grid. The span function returns the first track of a widget, how many
+// it spans and the size it needs.
+func measureTracks(widgets []*griddedWidget, count int, config map[int]GridTrack,
+	span func(Grid, render.Rect) (first, n, size int)) []int {
+	var sizes = make([]int, count)
+	for i := range sizes {
+		sizes[i] = config[i].MinSize
+	}
+
+	// The widgets in one track first, then the ones spanning more tracks
+	// grow them if they don't fit already.
+	for _, spanning := range []bool{false, true} {
+		for _, row := range widgets {
+			if row.widget.Hidden() {
+				continue
+			}
+
+			first, n, size := span(row.grid, row.natural)
+			if (n > 1) != spanning {
+				continue
+			}
+
+			tracks := sizes[first : first+n]
+			if extra := size - sumInts(tracks); extra > 0 {
+				growTracks(tracks, weightsOf(config, first, n), extra)
+			}
+		}
+	}
+	return sizes
+}
+
+// weightsOf returns the weights of n tracks from the first one.
+func weightsOf(config map[int]GridTrack, first, n int) []int {
+	var weights = make([]int, n)
+	for i := range weights {
+		weights[i] = config[first+i].Weight
+	}
+	return weights
+}
+
+// growTracks shares extra pixels between tracks by their weights, or evenly
+// if none of them has a weight. The last track gets the rounding remainder.
+func growTracks(sizes, weights []int, extra int) {
+	var total = sumInts(weights)
+	if total == 0 {
+		for i := range weights {
+			weights[i] = 1
+		}
+		total = len(weights)
+	}
+
+	var given int
+	for i := range sizes {
+		share := extra * weights[i] / total
+		if i == len(sizes)-1 {
+			share = extra - given
+		}
+		sizes[i] += share
+		given += share
+	}
+}
+
+// computeGrid lays out the gridded widgets inside the Frame, giving its
+// extra space to the weighted rows and columns.
+func (w *Frame) computeGrid(e render.Engine) {
+	if len(w.grid.widgets) == 0 {
+		return
+	}
+
+	var (
+		S       = w.Size()
+		inner   = render.NewRect(S.W-w.BoxThickness(2), S.H-w.BoxThickness(2))
+		widths  = append([]int{}, w.grid.widths...)
+		heights = append([]int{}, w.grid.heights...)
+	)
+	if extra := inner.W - sumInts(widths); extra > 0 && len(widths) > 0 {
+		if weights := weightsOf(w.grid.columns, 0, len(widths)); sumInts(weights) > 0 {
+			growTracks(widths, weights, extra)
+		}
+	}
+	if extra := inner.H - sumInts(heights); extra > 0 && len(heights) > 0 {
+		if weights := weightsOf(w.grid.rows, 0, len(heights)); sumInts(weights) > 0 {
+			growTracks(heights, weights, extra)
+		}
+	}
+
+	for _, row := range w.grid.widgets {
+		if row.widget.Hidden() {
+			continue
+		}
+
+		var (
+			g    = row.grid
+			cell = render.Rect{
+				X: sumInts(widths[:g.Column]),
+				Y: sumInts(heights[:g.Row]),
+				W: sumInts(widths[g.Column : g.Column+g.ColumnSpan]),
+				H: sumInts(heights[g.Row : g.Row+g.RowSpan]),
+			}
+			resize = row.natural
+		)
+
+		// Stretch between opposite sides.
+		if g.Sticky&StickyEW == StickyEW {
+			resize.W = cell.W - g.PadX*2
+		}
+		if g.Sticky&StickyNS == StickyNS {
+			resize.H = cell.H - g.PadY*2
+		}
+		if resize != row.widget.Size() {
+			row.widget.ResizeAuto(resize)
+			computeChild(row.widget, e)
+		}
+		row.assigned = row.widget.Size()
+
+		row.widget.MoveTo(render.NewPoint(
+			alignInCell(cell.X, cell.W, resize.W, g.PadX, g.Sticky&StickyW != 0, g.Sticky&StickyE != 0),
+			alignInCell(cell.Y, cell.H, resize.H, g.PadY, g.Sticky&StickyN != 0, g.Sticky&StickyS != 0),
+		))
+	}
+}
+
+// alignInCell returns the position of a widget in a cell along one axis:
+// against the start or end of the cell, or centered.
+func alignInCell(start, length, size, pad int, toStart, toEnd bool) int {
+	if toStart {
+		return start + pad
+	} else if toEnd {
+		return start + length - pad - size
+	}
+	return start + (length-size)/2
+}
+
+// sumInts returns the sum of the ints.
+func sumInts(values []int) int {
+	var sum int
+	for _, v := range values {
+		sum += v
+	}
+	return sum
+}
@@ -31,6 +31,18 @@ func (w *Frame) Pack(child Widget, config ...Pack) {
 		C = config[0]
 	}
 
+	// Padding: if the user only provided Padding add it to both
+	// the X and Y value. If the user additionally provided the X
+	// and Y value, it will add to the base padding as you'd expect.
+	C.PadX += C.Padding
+	C.PadY += C.Padding
+
+	// Fill: true implies both directions.
+	if C.Fill {
+		C.FillX = true
+		C.FillY = true
+	}
+
 	// Update an already placed widget.
 	for _, current := range w.packs[C.Side] {
 		if current.widget == child {
@@ -44,18 +56,6 @@ func (w *Frame) Pack(child Widget, config ...Pack) {
 		w.packs[C.Side] = []*packedWidget{}
 	}
 
-	// Padding: if the user only provided Padding add it to both
-	// the X and Y value. If the user additionally provided the X
-	// and Y value, it will add to the base padding as you'd expect.
-	C.PadX += C.Padding
-	C.PadY += C.Padding
-
-	// Fill: true implies both directions.
-	if C.Fill {
-		C.FillX = true
-		C.FillY = true
-	}
-
 	// Adopt the child widget so it can access the Frame.
 	child.SetParent(w)
 
@@ -66,6 +66,18 @@ func (w *Frame) Pack(child Widget, config ...Pack) {
 	w.Add(child)
 }
 
+// packOf returns the Pack config of a packed child widget.
+func (w *Frame) packOf(child Widget) (Pack, bool) {
+	for _, widgets := range w.packs {
+		for _, packed := range widgets {
+			if packed.widget == child {
+				return packed.pack, true
+			}
+		}
+	}
+	return Pack{}, false
+}
+
 // Unpack removes the widget from the packed lists.
 func (w *Frame) Unpack(child Widget) bool {
 	var any = false
@@ -91,6 +103,9 @@ func (w *Frame) Unpack(child Widget) bool {
 			w.packs[side] = replace
 		}
 	}
+	if any {
+		w.Invalidate()
+	}
 	return any
 }
 
@@ -136,7 +151,7 @@ func (w *Frame) computePacked(e render.Engine) {
 
 			child := packedWidget.widget
 			pack := packedWidget.pack
-			child.Compute(e)
+			computeChild(child, e)
 
 			if child.Hidden() {
 				continue
@@ -202,10 +217,27 @@ func (w *Frame) computePacked(e render.Engine) {
 				W: size.W + growBy.W,
 				H: size.H + growBy.H,
 			})
-			pw.widget.Compute(e)
+			computeChild(pw.widget, e)
 		}
 	}
 
+	// Make room for the grid too, which fills the inside of the Frame (see
+	// computeGrid).
+	if len(w.grid.widgets) > 0 {
+		grid := w.measureGrid(e)
+		maxWidth = maxInt(maxWidth, grid.W+w.BoxThickness(4))
+		maxHeight = maxInt(maxHeight, grid.H+w.BoxThickness(4))
+	}
+
+	// And for the flexed widgets, which flow in the Frame's current size
+	// (see computeFlex).
+	var flexSize render.Rect
+	if len(w.flex.widgets) > 0 {
+		flexSize = w.computeFlex(e)
+		maxWidth = maxInt(maxWidth, flexSize.W+w.BoxThickness(4))
+		maxHeight = maxInt(maxHeight, flexSize.H+w.BoxThickness(4))
+	}
+
 	// If we're not using a fixed Frame size, use the dynamically computed one.
 	if !w.FixedSize() {
 		frameSize = render.NewRect(maxWidth, maxHeight)
@@ -219,6 +251,11 @@ func (w *Frame) computePacked(e render.Engine) {
 		}
 	}
 
+	// The flexed widgets' lines set the size across them.
+	if len(w.flex.widgets) > 0 {
+		frameSize = w.flexFrameSize(frameSize, flexSize)
+	}
+
 	// Rescan all the widgets in this side to re-center them
 	// in their space.
 	innerFrameSize := render.NewRect(
@@ -283,7 +320,7 @@ func (w *Frame) computePacked(e render.Engine) {
 
 		if resized && size != resize {
 			child.ResizeAuto(resize)
-			child.Compute(e)
+			computeChild(child, e)
 		}
 		if moved {
 			child.MoveTo(point)
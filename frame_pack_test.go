@@ -0,0 +1,20 @@
+package ui
+
+import "testing"
+
+func TestRepackPadding(t *testing.T) {
+	var (
+		frame = NewFrame("Frame")
+		child = NewFrame("Child")
+	)
+	frame.Pack(child, Pack{Side: W, Padding: 4})
+	frame.Pack(child, Pack{Side: W, Padding: 2, PadX: 1, Fill: true})
+
+	pack, ok := frame.packOf(child)
+	if !ok {
+		t.Fatal("expected the child to be packed")
+	}
+	if pack.PadX != 3 || pack.PadY != 2 || !pack.FillX || !pack.FillY {
+		t.Errorf("expected a re-Pack to apply Padding and Fill, got %+v", pack)
+	}
+}
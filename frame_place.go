@@ -42,7 +42,10 @@ func (w *Frame) Place(child Widget, config Place) {
 	// Update an already placed widget.
 	for _, current := range w.placed {
 		if current.widget == child {
-			current.place = config
+			if current.place != config {
+				current.place = config
+				w.Invalidate()
+			}
 			return
 		}
 	}
@@ -72,7 +75,7 @@ func (w *Frame) computePlaced(e render.Engine) {
 		switch row.place.Strategy() {
 		case "Point":
 			row.widget.MoveTo(row.place.Point)
-			row.widget.Compute(e)
+			computeChild(row.widget, e)
 		case "Side":
 			var moveTo render.Point
 
@@ -100,7 +103,7 @@ func (w *Frame) computePlaced(e render.Engine) {
 			}
 
 			row.widget.MoveTo(moveTo)
-			row.widget.Compute(e)
+			computeChild(row.widget, e)
 		}
 
 		// If this widget itself has placed widgets, call its function too.
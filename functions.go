@@ -80,3 +80,11 @@ func widgetInFocusedWindow(w Widget) (isManaged, isFocused bool) {
 		}
 	}
 }
+
+// absInt returns the absolute value of an int.
+func absInt(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
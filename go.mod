@@ -6,4 +6,5 @@ require (
 	git.kirsle.net/go/render v0.0.0-20220505053906-129a24300dfa
 	github.com/veandco/go-sdl2 v0.4.36 // indirect
 	golang.org/x/image v0.14.0
+	gopkg.in/yaml.v3 v3.0.1
 )
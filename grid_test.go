@@ -0,0 +1,33 @@
+package ui_test
+
+import (
+	"testing"
+
+	"git.kirsle.net/go/render"
+	"git.kirsle.net/go/ui"
+	"git.kirsle.net/go/ui/softrender"
+)
+
+// A widget stretched across a grid cell shrinks again with the grid.
+func TestGridShrink(t *testing.T) {
+	var (
+		engine = softrender.New(400, 200)
+		grid   = ui.NewFrame("Grid")
+		child  = ui.NewFrame("Child")
+	)
+	child.Resize(render.NewRect(50, 20))
+	grid.GridColumn(0, ui.GridTrack{Weight: 1})
+	grid.Grid(child, ui.Grid{Sticky: ui.StickyEW})
+
+	for _, width := range []int{300, 100, 30} {
+		grid.Resize(render.NewRect(width, 20))
+		grid.Compute(engine)
+		expect := width - grid.BoxThickness(2)
+		if width < 50 {
+			expect = 50 // not smaller than its own size
+		}
+		if got := child.Size().W; got != expect {
+			t.Errorf("grid of %dpx: expected the child to be %dpx wide, got %d", width, expect, got)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package ui
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"git.kirsle.net/go/render"
+)
+
+/*
+hittest.go holds the Supervisor's spatial index for finding the widgets under
+the mouse cursor without checking every supervised widget on every tick.
+
+The absolute rect of each supervised widget is cached and bucketed into a
+uniform grid of hitCellSize pixels. The cache is rebuilt when a widget is
+added or removed, or when a widget in the trees of the supervised widgets
+moves or resizes: each change is counted up to the root of its tree (see
+geometryChanged), so only the roots need to be checked.
+*/
+
+// hitCellSize is the width and height in pixels of a cell of the hit-test grid.
+const hitCellSize = 64
+
+// hitMaxCells is the most cells a widget is bucketed into; larger widgets are
+// kept in a list that is checked on every lookup instead.
+const hitMaxCells = 256
+
+// hitCell is the coordinate of a cell in the hit-test grid.
+type hitCell struct {
+	X int
+	Y int
+}
+
+// hitIndex is the spatial index of the supervised widgets. The ints are
+// indexes into Supervisor.widgets, kept in ascending order.
+type hitIndex struct {
+	lock    sync.Mutex    // concurrent readers of the Supervisor may rebuild it
+	roots   []geometer    // the roots of the trees of the widgets
+	version uint64        // the sum of the roots' geometry counts when built
+	dirty   bool          // widgets were added or removed since the build
+	rects   []render.Rect // cached AbsoluteRect of each widget
+	cells   map[hitCell][]int
+	large   []int // widgets that span more than hitMaxCells
+}
+
+// queryHits returns the indexes of the supervised widgets whose rects hold
+// the point, in ascending order, rebuilding the spatial index first if it's
+// stale. The caller must hold the Supervisor's lock, at least for reading;
+// the index has a lock of its own as two readers may both rebuild it.
+func (s *Supervisor) queryHits(p render.Point) []int {
+	var idx = s.hitIndex
+	idx.lock.Lock()
+	defer idx.lock.Unlock()
+
+	if idx.dirty || idx.cells == nil || idx.version != idx.geometryVersion() {
+		idx.build(s.widgets)
+	}
+	return idx.query(p)
+}
+
+// geometryVersion sums the geometry counts of the roots. The counts only go
+// up, so the sum changes when any of them does.
+func (idx *hitIndex) geometryVersion() uint64 {
+	var version uint64
+	for _, root := range idx.roots {
+		version += atomic.LoadUint64(root.geometryCounter())
+	}
+	return version
+}
+
+// build the index from scratch.
+func (idx *hitIndex) build(widgets []WidgetSlot) {
+	idx.dirty = false
+	idx.rects = make([]render.Rect, len(widgets))
+	idx.cells = map[hitCell][]int{}
+	idx.large = []int{}
+
+	// The roots of the trees of the widgets. A widget moved into another
+	// tree is counted by its old root too, see SetParent.
+	idx.roots = []geometer{}
+	var seen = map[Widget]bool{}
+	for _, slot := range widgets {
+		var root = slot.widget
+		for parent, ok := root.Parent(); ok; parent, ok = parent.Parent() {
+			root = parent
+		}
+		if g, ok := root.(geometer); ok && !seen[root] {
+			seen[root] = true
+			idx.roots = append(idx.roots, g)
+		}
+	}
+	idx.version = idx.geometryVersion()
+
+	for i, slot := range widgets {
+		var (
+			P = AbsolutePosition(slot.widget)
+			S = slot.widget.Size()
+			R = clipToParents(slot.widget, render.Rect{X: P.X, Y: P.Y, W: S.W, H: S.H})
+		)
+		idx.rects[i] = R
+		if R.W <= 0 || R.H <= 0 {
+			continue
+		}
+
+		var (
+			x1 = floorDiv(R.X, hitCellSize)
+			y1 = floorDiv(R.Y, hitCellSize)
+			x2 = floorDiv(R.X+R.W-1, hitCellSize)
+			y2 = floorDiv(R.Y+R.H-1, hitCellSize)
+		)
+		if (x2-x1+1)*(y2-y1+1) > hitMaxCells {
+			idx.large = append(idx.large, i)
+			continue
+		}
+
+		for y := y1; y <= y2; y++ {
+			for x := x1; x <= x2; x++ {
+				cell := hitCell{x, y}
+				idx.cells[cell] = append(idx.cells[cell], i)
+			}
+		}
+	}
+}
+
+// clipper is a container that clips its children, like a clipped Frame.
+type clipper interface {
+	clipRect() (render.Rect, bool)
+}
+
+// clipToParents limits a widget's absolute rect to the clip rects of its
+// parents, so the parts of widgets scrolled out of view aren't hit.
+func clipToParents(w Widget, R render.Rect) render.Rect {
+	for parent, ok := w.Parent(); ok; parent, ok = parent.Parent() {
+		if c, ok := parent.(clipper); ok {
+			if clip, ok := c.clipRect(); ok {
+				R = intersectRect(clip, R)
+			}
+		}
+	}
+	return R
+}
+
+// query returns the indexes of the widgets whose rects hold the point, in
+// ascending order.
+func (idx *hitIndex) query(p render.Point) []int {
+	var (
+		cell   = hitCell{floorDiv(p.X, hitCellSize), floorDiv(p.Y, hitCellSize)}
+		result = []int{}
+		inside = func(i int) bool {
+			R := idx.rects[i]
+			return p.X >= R.X && p.X < R.X+R.W && p.Y >= R.Y && p.Y < R.Y+R.H
+		}
+	)
+
+	for _, i := range idx.cells[cell] {
+		if inside(i) {
+			result = append(result, i)
+		}
+	}
+	for _, i := range idx.large {
+		if inside(i) {
+			result = append(result, i)
+		}
+	}
+
+	if len(idx.large) > 0 {
+		sort.Ints(result)
+	}
+	return result
+}
+
+// floorDiv divides rounding towards negative infinity, so that negative
+// coordinates fall into the right grid cells.
+func floorDiv(a, b int) int {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
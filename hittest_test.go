@@ -0,0 +1,156 @@
+package ui
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"git.kirsle.net/go/render"
+	"git.kirsle.net/go/render/event"
+)
+
+// supervisorGrid returns a Supervisor with n buttons laid out in a grid.
+func supervisorGrid(n int) (*Supervisor, []*Button) {
+	var (
+		s       = NewSupervisor()
+		buttons = make([]*Button, n)
+	)
+	for i := range buttons {
+		btn := NewButton(fmt.Sprintf("Button %d", i), NewLabel(Label{Text: "Hello"}))
+		btn.MoveTo(render.NewPoint((i%100)*40, (i/100)*20))
+		btn.Resize(render.NewRect(40, 20))
+		s.Add(btn)
+		buttons[i] = btn
+	}
+	return s, buttons
+}
+
+func TestHitIndex(t *testing.T) {
+	var (
+		s   = NewSupervisor()
+		rng = rand.New(rand.NewSource(1))
+	)
+	for i := 0; i < 500; i++ {
+		frame := NewFrame(fmt.Sprintf("Frame %d", i))
+		frame.MoveTo(render.NewPoint(rng.Intn(2000)-500, rng.Intn(2000)-500))
+		if i%50 == 0 {
+			frame.Resize(render.NewRect(3000, 3000)) // spans many cells
+		} else {
+			frame.Resize(render.NewRect(rng.Intn(200), rng.Intn(200)))
+		}
+		s.Add(frame)
+	}
+
+	// Compare against checking every widget.
+	check := func() {
+		for i := 0; i < 200; i++ {
+			var (
+				XY      = render.NewPoint(rng.Intn(2000)-500, rng.Intn(2000)-500)
+				hits, _ = s.Hovering(XY)
+				expect  = []Widget{}
+			)
+			for _, child := range s.widgets {
+				R := AbsoluteRect(child.widget)
+				if XY.X >= R.X && XY.X < R.X+R.W && XY.Y >= R.Y && XY.Y < R.Y+R.H {
+					expect = append(expect, child.widget)
+				}
+			}
+
+			if len(hits) != len(expect) {
+				t.Fatalf("at %s: expected %d widgets, got %d", XY, len(expect), len(hits))
+			}
+			for j := range hits {
+				if hits[j].widget != expect[j] {
+					t.Fatalf("at %s: widget %d: expected %s, got %s", XY, j, expect[j], hits[j].widget)
+				}
+			}
+		}
+	}
+	check()
+
+	// Moving widgets invalidates the cached rects.
+	for _, child := range s.widgets {
+		child.widget.MoveTo(render.NewPoint(rng.Intn(2000)-500, rng.Intn(2000)-500))
+	}
+	check()
+}
+
+// The index of a Supervisor is only rebuilt when the trees of its own widgets
+// change, including when an ancestor of a widget moves.
+func TestHitIndexGeometry(t *testing.T) {
+	var (
+		s, _     = supervisorGrid(10)
+		_, other = supervisorGrid(10)
+		root     = NewFrame("Root")
+		frame    = NewFrame("Parent")
+		button   = NewButton("Child", NewLabel(Label{Text: "Hello"}))
+	)
+	root.Resize(render.NewRect(400, 400))
+	root.Place(frame, Place{})
+	frame.Resize(render.NewRect(100, 100))
+	frame.Place(button, Place{Top: 10, Left: 10})
+	button.MoveTo(render.NewPoint(10, 10)) // as the frame would place it
+	button.Resize(render.NewRect(40, 20))
+	frame.MoveTo(render.NewPoint(0, 100))
+	s.Add(button)
+
+	if hits, _ := s.Hovering(render.NewPoint(20, 120)); len(hits) != 1 || hits[0].widget != button {
+		t.Errorf("expected the button in its parent, got %v", hits)
+	}
+	other[0].MoveTo(render.NewPoint(500, 500))
+	if s.hitIndex.version != s.hitIndex.geometryVersion() {
+		t.Errorf("expected another Supervisor's widgets not to affect the index")
+	}
+
+	frame.MoveTo(render.NewPoint(200, 100))
+	if hits, _ := s.Hovering(render.NewPoint(220, 120)); len(hits) != 1 || hits[0].widget != button {
+		t.Errorf("expected the button to move with its parent, got %v", hits)
+	}
+}
+
+// Readers may look up the widgets from several goroutines, rebuilding the
+// index after the widgets moved. Run with -race.
+func TestHitIndexConcurrent(t *testing.T) {
+	var (
+		s, buttons = supervisorGrid(200)
+		done       = make(chan bool)
+	)
+	buttons[0].MoveTo(render.NewPoint(10, 10))
+
+	for i := 0; i < 4; i++ {
+		go func(i int) {
+			s.Hovering(render.NewPoint(i*40, 0))
+			done <- true
+		}(i)
+	}
+	for i := 0; i < 4; i++ {
+		<-done
+	}
+}
+
+func BenchmarkHovering(b *testing.B) {
+	for _, n := range []int{100, 1000, 5000} {
+		s, _ := supervisorGrid(n)
+		b.Run(fmt.Sprintf("%d widgets", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				s.hitWidgets(render.NewPoint(i%4000, (i/4000)%1000))
+			}
+		})
+	}
+}
+
+func BenchmarkSupervisorLoop(b *testing.B) {
+	for _, n := range []int{100, 1000, 5000} {
+		var (
+			s, _ = supervisorGrid(n)
+			ev   = event.NewState()
+		)
+		b.Run(fmt.Sprintf("%d widgets", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				ev.CursorX = i % 4000
+				ev.CursorY = (i / 4000) % 1000
+				s.Loop(ev)
+			}
+		})
+	}
+}
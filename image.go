@@ -91,6 +91,8 @@ func (w *Image) ReplaceFromImage(im image.Image) error {
 		w.texture = nil
 	}
 	w.Image = im
+	w.geometryChanged()
+	w.Invalidate()
 	return nil
 }
 
@@ -0,0 +1,653 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"git.kirsle.net/go/render"
+	"git.kirsle.net/go/render/event"
+)
+
+// Inspector is a Window that shows the live widget tree, for debugging
+// layouts. Clicking a widget in the tree selects it: its bounds, padding,
+// border and margin are highlighted on screen and its properties are shown
+// and may be edited. The Pick button selects the widget under the cursor.
+type Inspector struct {
+	*Window
+
+	// Config settings.
+	Title      string
+	Root       Widget // widget tree to inspect; default is the supervised widgets
+	Supervisor *Supervisor
+	Engine     render.Engine // optional: allows editing colors with a ColorPicker
+
+	selected Widget
+	hovered  Widget // widget under the cursor in pick mode
+	picking  bool
+	expanded map[Widget]bool
+	scroll   int // index of the first node shown in the tree
+
+	rows     []*inspectorRow
+	props    []inspectorProp
+	pick     *Frame // full screen modal that grabs the clicks in pick mode
+	pickArea *Frame // its child, which receives the mouse events
+	overlay  *inspectorOverlay
+	screen   render.Rect // window size when the overlay was last presented
+}
+
+// inspectorRow is one line of the widget tree.
+type inspectorRow struct {
+	frame  *Frame
+	toggle *Button
+	button *Button
+	expand string // "+", "-" or "" for widgets with no children
+	text   string
+	widget Widget
+}
+
+// inspectorProp is a property of the selected widget.
+type inspectorProp struct {
+	name  string
+	value string
+}
+
+// inspectorNode is a widget in the flattened tree.
+type inspectorNode struct {
+	widget Widget
+	depth  int
+}
+
+// DefaultInspectorSize is the window size used by NewInspector.
+var DefaultInspectorSize = render.Rect{W: 360, H: 520}
+
+// InspectorRows is the number of rows of the widget tree shown at once. The
+// mouse wheel scrolls through the rest.
+var InspectorRows = 12
+
+// Colors used by the Inspector to highlight the selected widget.
+var (
+	InspectorBoundsColor  = render.RGBA(255, 255, 0, 255)   // the widget's Rect
+	InspectorPaddingColor = render.RGBA(0, 204, 0, 255)     // Pack padding around the widget
+	InspectorBorderColor  = render.RGBA(255, 153, 0, 255)   // inside the outline and border
+	InspectorMarginColor  = render.RGBA(0, 153, 255, 96)    // inside the margin
+	InspectorPickColor    = render.RGBA(255, 0, 255, 255)   // widget under the cursor in pick mode
+	InspectorSelectColor  = render.RGBA(153, 204, 255, 255) // selected row of the tree
+)
+
+// NewInspector creates a new Inspector window. It starts hidden: call Show()
+// to open it, e.g. from a keyboard shortcut.
+func NewInspector(config Inspector) (*Inspector, error) {
+	if config.Title == "" {
+		config.Title = "Inspector"
+	}
+	if config.Supervisor == nil {
+		return nil, errors.New("a ui.Supervisor is required")
+	}
+
+	window := NewWindow(config.Title)
+	window.Resize(DefaultInspectorSize)
+	window.SetButtons(CloseButton)
+
+	w := &Inspector{
+		Window:     window,
+		Title:      config.Title,
+		Root:       config.Root,
+		Supervisor: config.Supervisor,
+		Engine:     config.Engine,
+		expanded:   map[Widget]bool{},
+		pick:       NewFrame("Inspector Pick"),
+		pickArea:   NewFrame("Inspector Pick Area"),
+	}
+	w.overlay = &inspectorOverlay{inspector: w}
+	w.overlay.IDFunc(func() string {
+		return "InspectorOverlay<>"
+	})
+
+	w.setup()
+
+	// Keep the tree and properties up to date as the window is drawn.
+	window.Handle(Compute, func(ed EventData) error {
+		w.Refresh()
+		return nil
+	})
+	window.Handle(CloseWindow, func(ed EventData) error {
+		w.SetPicking(false)
+		return nil
+	})
+
+	// The pick mode modal keeps covering the window when it's resized.
+	w.Supervisor.OnLoop(func(ev *event.State) {
+		if w.picking {
+			w.fitPick()
+		}
+	})
+
+	w.Window.Supervise(w.Supervisor)
+	w.Supervisor.DrawOnTop(w.overlay)
+	w.Window.Hide()
+	return w, nil
+}
+
+// setup the UI of the Inspector window.
+func (w *Inspector) setup() {
+	var (
+		font = DefaultFont.Update(render.Text{
+			Size: 10,
+		})
+		toolbar = NewFrame("Inspector Toolbar")
+		tree    = NewFrame("Inspector Tree")
+		props   = NewFrame("Inspector Properties")
+		edit    = NewFrame("Inspector Edit")
+	)
+	w.Pack(toolbar, Pack{Side: N, FillX: true, PadY: 2})
+	w.Pack(tree, Pack{Side: N, FillX: true})
+	w.Pack(props, Pack{Side: N, FillX: true, PadY: 4})
+	w.Pack(edit, Pack{Side: N, FillX: true})
+
+	// newButton makes a small supervised button.
+	newButton := func(name string, text *string, fn func()) *Button {
+		btn := NewButton(name, NewLabel(Label{
+			TextVariable: text,
+			Font: font.Update(render.Text{
+				PadX: 4,
+			}),
+		}))
+		btn.Handle(Click, func(ed EventData) error {
+			fn()
+			return nil
+		})
+		w.Supervisor.Add(btn)
+		return btn
+	}
+	label := func(text string) *string {
+		return &text
+	}
+
+	// Toolbar.
+	toolbar.Pack(newButton("Inspector Pick", label("Pick"), func() {
+		w.SetPicking(!w.picking)
+	}), Pack{Side: W, PadX: 2})
+	toolbar.Pack(newButton("Inspector Collapse", label("Collapse"), func() {
+		w.expanded = map[Widget]bool{}
+		w.scroll = 0
+	}), Pack{Side: W, PadX: 2})
+
+	// The rows of the widget tree.
+	for i := 0; i < InspectorRows; i++ {
+		row := &inspectorRow{
+			frame: NewFrame(fmt.Sprintf("Inspector Row %d", i)),
+		}
+		row.toggle = newButton(fmt.Sprintf("Inspector Toggle %d", i), &row.expand, func() {
+			if row.widget != nil {
+				w.Expand(row.widget, !w.expanded[row.widget])
+			}
+		})
+		row.toggle.Resize(render.NewRect(16, 16))
+		row.button = newButton(fmt.Sprintf("Inspector Node %d", i), &row.text, func() {
+			if row.widget != nil {
+				w.Select(row.widget)
+			}
+		})
+		row.frame.Pack(row.toggle, Pack{Side: W})
+		row.frame.Pack(row.button, Pack{Side: W, FillX: true, Expand: true})
+		tree.Pack(row.frame, Pack{Side: N, FillX: true})
+		w.rows = append(w.rows, row)
+	}
+
+	// The mouse wheel scrolls the tree.
+	tree.HandleBubble(Scroll, func(ed EventData) error {
+		if ed.WheelY != 0 {
+			w.scroll -= ed.WheelY
+			return ErrStopPropagation
+		}
+		return nil
+	})
+	w.Supervisor.Add(tree)
+
+	// Properties of the selected widget.
+	for _, name := range []string{
+		"ID", "Point", "Size", "BoxSize", "FixedSize", "Margin", "Border",
+		"Outline", "Background", "Foreground", "BorderColor", "Pack", "State",
+	} {
+		w.props = append(w.props, inspectorProp{name: name})
+	}
+	for i := range w.props {
+		prop := &w.props[i]
+		row := NewFrame("Inspector Property " + prop.name)
+		row.Pack(NewLabel(Label{
+			Text: prop.name + ":",
+			Font: font,
+		}), Pack{Side: W, PadX: 2})
+		row.Pack(NewLabel(Label{
+			TextVariable: &prop.value,
+			Font:         font,
+		}), Pack{Side: W, PadX: 2})
+		props.Pack(row, Pack{Side: N, FillX: true})
+	}
+
+	// Editing the selected widget: sizes.
+	for _, field := range []struct {
+		name string
+		fn   func(Widget, int)
+	}{
+		{"Width", func(sel Widget, delta int) {
+			size := sel.Size()
+			sel.Resize(render.NewRect(maxInt(size.W+delta, 0), size.H))
+		}},
+		{"Height", func(sel Widget, delta int) {
+			size := sel.Size()
+			sel.Resize(render.NewRect(size.W, maxInt(size.H+delta, 0)))
+		}},
+		{"Margin", func(sel Widget, delta int) {
+			sel.SetMargin(maxInt(sel.Margin()+delta, 0))
+		}},
+		{"Border", func(sel Widget, delta int) {
+			sel.SetBorderSize(maxInt(sel.BorderSize()+delta, 0))
+		}},
+	} {
+		field := field
+		row := NewFrame("Inspector Edit " + field.name)
+		row.Pack(NewLabel(Label{
+			Text: field.name + ":",
+			Font: font,
+		}), Pack{Side: W, PadX: 2})
+		for _, delta := range []int{-1, 1} {
+			delta := delta
+			text := "-"
+			if delta > 0 {
+				text = "+"
+			}
+			row.Pack(newButton(fmt.Sprintf("Inspector %s %s", field.name, text), label(text), func() {
+				if w.selected != nil {
+					field.fn(w.selected, delta)
+				}
+			}), Pack{Side: W, PadX: 1})
+		}
+		edit.Pack(row, Pack{Side: W, PadX: 4})
+	}
+
+	// Editing the selected widget: colors, with a ColorPicker.
+	if w.Engine != nil {
+		colors := NewFrame("Inspector Edit Colors")
+		for _, field := range []struct {
+			name string
+			get  func(Widget) render.Color
+			set  func(Widget, render.Color)
+		}{
+			{"Background", Widget.Background, Widget.SetBackground},
+			{"Foreground", Widget.Foreground, Widget.SetForeground},
+			{"BorderColor", Widget.BorderColor, Widget.SetBorderColor},
+		} {
+			field := field
+			colors.Pack(newButton("Inspector "+field.name, label(field.name), func() {
+				if w.selected != nil {
+					w.editColor(w.selected, field.name, field.get, field.set)
+				}
+			}), Pack{Side: W, PadX: 2})
+		}
+		w.Pack(colors, Pack{Side: N, FillX: true, PadY: 4})
+	}
+
+	// Pick mode: a modal over the whole screen follows the cursor. Only the
+	// children of a modal receive mouse events.
+	w.pick.Pack(w.pickArea, Pack{Side: N, Fill: true})
+	w.pickArea.Handle(MouseMove, func(ed EventData) error {
+		w.hovered = w.widgetAt(ed.Point)
+		return nil
+	})
+	w.pickArea.Handle(Click, func(ed EventData) error {
+		if node := w.widgetAt(ed.Point); node != nil {
+			w.Select(node)
+		}
+		w.SetPicking(false)
+		return nil
+	})
+	w.pickArea.Handle(RightClick, func(ed EventData) error {
+		w.SetPicking(false)
+		return nil
+	})
+	w.pick.Hide()
+	w.Supervisor.Add(w.pickArea)
+}
+
+// editColor opens a ColorPicker for a color property of a widget.
+func (w *Inspector) editColor(sel Widget, name string, get func(Widget) render.Color, set func(Widget, render.Color)) {
+	picker, err := NewColorPicker(ColorPicker{
+		Title:      name,
+		Color:      get(sel),
+		Supervisor: w.Supervisor,
+		Engine:     w.Engine,
+	})
+	if err != nil {
+		return
+	}
+	picker.Then(func(color render.Color) {
+		set(sel, color)
+	})
+	picker.MoveTo(render.NewPoint(w.Point().X+32, w.Point().Y+32))
+	picker.Show()
+	w.Supervisor.FocusWindow(picker.Window)
+}
+
+// Selected returns the selected widget, or nil.
+func (w *Inspector) Selected() Widget {
+	return w.selected
+}
+
+// Select a widget: its parents are expanded in the tree and it is
+// highlighted on screen.
+func (w *Inspector) Select(node Widget) {
+	w.selected = node
+	if node == nil {
+		return
+	}
+
+	crawlInspector(w.roots(), func(other Widget, ancestors []Widget) bool {
+		if other != node {
+			return true
+		}
+		for _, parent := range ancestors {
+			w.expanded[parent] = true
+		}
+		return false
+	})
+
+	// Scroll the tree to show it.
+	for i, n := range w.nodes() {
+		if n.widget == node {
+			if i < w.scroll || i >= w.scroll+len(w.rows) {
+				w.scroll = i - len(w.rows)/2
+			}
+			break
+		}
+	}
+}
+
+// Property returns a property of the selected widget as shown by the
+// Inspector, e.g. "Size" or "Pack".
+func (w *Inspector) Property(name string) string {
+	for _, prop := range w.props {
+		if prop.name == name {
+			return prop.value
+		}
+	}
+	return ""
+}
+
+// Expand or collapse a widget in the tree.
+func (w *Inspector) Expand(node Widget, v bool) {
+	if v {
+		w.expanded[node] = true
+	} else {
+		delete(w.expanded, node)
+	}
+}
+
+// Picking returns whether pick mode is on.
+func (w *Inspector) Picking() bool {
+	return w.picking
+}
+
+// SetPicking turns pick mode on or off. In pick mode, clicking anywhere on
+// screen selects the widget under the cursor instead of clicking it. The
+// right mouse button cancels.
+func (w *Inspector) SetPicking(v bool) {
+	if v == w.picking {
+		return
+	}
+	w.picking = v
+	w.hovered = nil
+
+	if v {
+		w.fitPick()
+		w.pick.Show()
+		w.Supervisor.PushModal(w.pick)
+	} else {
+		w.Supervisor.PopModal(w.pick)
+		w.pick.Hide()
+	}
+}
+
+// fitPick sizes the pick mode modal to cover the whole window, as told by
+// the Engine or else by the last Present of the overlay.
+func (w *Inspector) fitPick() {
+	var size = w.screen
+	if w.Engine != nil {
+		size = render.NewRect(w.Engine.WindowSize())
+	}
+	if w.pick.Size() != size {
+		w.pick.Resize(size)
+		w.pickArea.Resize(size)
+	}
+}
+
+// Refresh updates the widget tree and the properties of the selected widget.
+// It is called automatically whenever the Inspector window is computed.
+func (w *Inspector) Refresh() {
+	var nodes = w.nodes()
+
+	// Clamp the scroll position.
+	if w.scroll > len(nodes)-len(w.rows) {
+		w.scroll = len(nodes) - len(w.rows)
+	}
+	if w.scroll < 0 {
+		w.scroll = 0
+	}
+
+	for i, row := range w.rows {
+		if w.scroll+i >= len(nodes) {
+			row.widget = nil
+			row.frame.Hide()
+			continue
+		}
+
+		node := nodes[w.scroll+i]
+		row.widget = node.widget
+		row.text = strings.Repeat("  ", node.depth) + node.widget.ID()
+		row.expand = ""
+		if len(node.widget.Children()) > 0 {
+			if w.expanded[node.widget] {
+				row.expand = "-"
+			} else {
+				row.expand = "+"
+			}
+		}
+
+		// The selected row keeps its color while hovered.
+		if selected := node.widget == w.selected; selected != row.button.FixedColor {
+			row.button.FixedColor = selected
+			if selected {
+				row.button.SetBackground(InspectorSelectColor)
+			} else {
+				row.button.SetBackground(row.button.style.Background)
+			}
+		}
+		row.frame.Show()
+	}
+
+	for i := range w.props {
+		w.props[i].value = ""
+	}
+	if sel := w.selected; sel != nil {
+		var (
+			pack, packed = widgetPack(sel)
+			values       = map[string]string{
+				"ID":          sel.ID(),
+				"Point":       fmt.Sprintf("%d,%d (absolute %d,%d)", sel.Point().X, sel.Point().Y, AbsolutePosition(sel).X, AbsolutePosition(sel).Y),
+				"Size":        fmt.Sprintf("%dx%d", sel.Size().W, sel.Size().H),
+				"BoxSize":     fmt.Sprintf("%dx%d", sel.BoxSize().W, sel.BoxSize().H),
+				"FixedSize":   fmt.Sprintf("%v", sel.FixedSize()),
+				"Margin":      fmt.Sprintf("%d", sel.Margin()),
+				"Border":      fmt.Sprintf("%d %s", sel.BorderSize(), sel.BorderStyle()),
+				"Outline":     fmt.Sprintf("%d %s", sel.OutlineSize(), sel.OutlineColor().ToHex()),
+				"Background":  sel.Background().ToHex(),
+				"Foreground":  sel.Foreground().ToHex(),
+				"BorderColor": sel.BorderColor().ToHex(),
+				"State":       fmt.Sprintf("hidden=%v focused=%v", sel.Hidden(), hasFocus(sel)),
+			}
+		)
+		if packed {
+			values["Pack"] = fmt.Sprintf("side=%d pad=%d,%d fill=%v,%v expand=%v",
+				pack.Side, pack.PadX, pack.PadY, pack.FillX, pack.FillY, pack.Expand,
+			)
+		}
+		for i := range w.props {
+			w.props[i].value = values[w.props[i].name]
+		}
+	}
+}
+
+// roots returns the widget trees to inspect: the Root, or else each
+// supervised widget that has no parent.
+func (w *Inspector) roots() []Widget {
+	if w.Root != nil {
+		return []Widget{w.Root}
+	}
+
+	var roots []Widget
+	for _, slot := range w.Supervisor.slots() {
+		if _, ok := slot.widget.Parent(); ok || w.owns(slot.widget) {
+			continue
+		}
+		roots = append(roots, slot.widget)
+	}
+	return roots
+}
+
+// owns returns whether a widget is part of the Inspector itself.
+func (w *Inspector) owns(node Widget) bool {
+	return node == w.Window || node == w.pick || HasParent(node, w.Window)
+}
+
+// nodes flattens the expanded parts of the widget tree.
+func (w *Inspector) nodes() []inspectorNode {
+	var nodes []inspectorNode
+	crawlInspector(w.roots(), func(node Widget, ancestors []Widget) bool {
+		for _, parent := range ancestors {
+			if !w.expanded[parent] {
+				return true
+			}
+		}
+		nodes = append(nodes, inspectorNode{
+			widget: node,
+			depth:  len(ancestors),
+		})
+		return true
+	})
+	return nodes
+}
+
+// widgetAt returns the deepest visible widget under the cursor. Later roots
+// and children are drawn on top of earlier ones.
+func (w *Inspector) widgetAt(p render.Point) Widget {
+	var found Widget
+	crawlInspector(w.roots(), func(node Widget, ancestors []Widget) bool {
+		if !node.Hidden() && !w.owns(node) && p.Inside(AbsoluteRect(node)) {
+			found = node
+		}
+		return true
+	})
+	return found
+}
+
+// crawlInspector visits each of the widget trees depth first.
+func crawlInspector(roots []Widget, fn func(Widget, []Widget) bool) {
+	var stop bool
+	for _, root := range roots {
+		crawlTree(root, func(node Widget, ancestors []Widget) bool {
+			stop = !fn(node, ancestors)
+			return !stop
+		})
+		if stop {
+			return
+		}
+	}
+}
+
+// widgetPack returns the Pack config of a widget in its parent Frame.
+func widgetPack(node Widget) (Pack, bool) {
+	if parent, ok := node.Parent(); ok {
+		if frame, ok := parent.(*Frame); ok {
+			return frame.packOf(node)
+		}
+	}
+	return Pack{}, false
+}
+
+// maxInt returns the larger of two ints.
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// inspectorOverlay draws the Inspector's highlights on top of everything.
+type inspectorOverlay struct {
+	BaseWidget
+	inspector *Inspector
+}
+
+// Present the highlights of the selected widget, or of the widget under the
+// cursor in pick mode.
+func (w *inspectorOverlay) Present(e render.Engine, P render.Point) {
+	var i = w.inspector
+	if i.Window.Hidden() {
+		return
+	}
+
+	// Remember the window size for the pick mode modal, see fitPick.
+	i.screen = render.NewRect(e.WindowSize())
+
+	if i.picking && i.hovered != nil {
+		e.DrawRect(InspectorPickColor, AbsoluteRect(i.hovered))
+		return
+	}
+
+	sel := i.selected
+	if sel == nil || sel.Hidden() {
+		return
+	}
+
+	var (
+		rect    = AbsoluteRect(sel)
+		border  = sel.OutlineSize() + sel.BorderSize()
+		margin  = sel.BoxThickness(1)
+		pack, _ = widgetPack(sel)
+	)
+
+	// Margin: the content area inside the border and margin.
+	if inner := insetRect(rect, margin); inner.W > 0 && inner.H > 0 {
+		e.DrawBox(InspectorMarginColor, inner)
+	}
+
+	// Border: inside the outline and border.
+	if border > 0 {
+		e.DrawRect(InspectorBorderColor, insetRect(rect, border))
+	}
+
+	// Padding: the Pack padding around the widget. Its PadX and PadY
+	// include the Padding on both axes, as the Frame lays it out.
+	if pack.PadX > 0 || pack.PadY > 0 {
+		e.DrawRect(InspectorPaddingColor, render.Rect{
+			X: rect.X - pack.PadX,
+			Y: rect.Y - pack.PadY,
+			W: rect.W + pack.PadX*2,
+			H: rect.H + pack.PadY*2,
+		})
+	}
+
+	// Bounds.
+	e.DrawRect(InspectorBoundsColor, rect)
+}
+
+// insetRect shrinks a rect by n pixels on each side.
+func insetRect(r render.Rect, n int) render.Rect {
+	return render.Rect{
+		X: r.X + n,
+		Y: r.Y + n,
+		W: r.W - n*2,
+		H: r.H - n*2,
+	}
+}
@@ -0,0 +1,69 @@
+package ui_test
+
+import (
+	"fmt"
+	"testing"
+
+	"git.kirsle.net/go/render"
+	"git.kirsle.net/go/ui"
+	"git.kirsle.net/go/ui/uitest"
+)
+
+func TestInspector(t *testing.T) {
+	var (
+		s      = ui.NewSupervisor()
+		root   = ui.NewFrame("Root")
+		panel  = ui.NewFrame("Panel")
+		button = ui.NewButton("OK", testLabel("OK"))
+	)
+	root.Resize(render.NewRect(uitest.DefaultWidth, uitest.DefaultHeight))
+	root.Pack(panel, ui.Pack{Side: ui.NW})
+	panel.Pack(button, ui.Pack{Side: ui.W, PadX: 4})
+	s.Add(button)
+
+	ins, err := ui.NewInspector(ui.Inspector{
+		Supervisor: s,
+		Root:       root,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ins.MoveTo(render.NewPoint(200, 0))
+	ins.Show()
+
+	d := uitest.New(t, s, root)
+	d.Tick(1)
+
+	// Pick the widget under the cursor.
+	d.Click("Button<Inspector Pick>")
+	if !ins.Picking() {
+		t.Fatal("expected pick mode to be on")
+	}
+	d.ClickPoint(uitest.Center(button))
+	if ins.Picking() {
+		t.Error("expected pick mode to end after a click")
+	}
+	if sel := ins.Selected(); sel == nil || sel.ID() != `Label<"OK">` {
+		t.Fatalf("expected the button's label to be picked, got %v", sel)
+	}
+
+	// Select the button: the tree expands down to it.
+	ins.Select(button)
+	d.Tick(1)
+	for i, expect := range []string{"Frame<Root>", "  Frame<Panel>", "    Button<OK>"} {
+		row := ui.FindByID(ins.Window, fmt.Sprintf("Button<Inspector Node %d>", i))
+		if got := row.Children()[0].(*ui.Label).Value(); got != expect {
+			t.Errorf("tree row %d: expected %q, got %q", i, expect, got)
+		}
+	}
+	if got := ins.Property("Pack"); got == "" {
+		t.Error("expected the button's Pack property")
+	}
+
+	// Edit its width.
+	width := button.Size().W
+	d.Click("Button<Inspector Width +>")
+	if got := button.Size().W; got != width+1 || !button.FixedSize() {
+		t.Errorf("expected the button to be resized to width %d, got %d", width+1, got)
+	}
+}
@@ -0,0 +1,283 @@
+package ui
+
+import (
+	"sync/atomic"
+
+	"git.kirsle.net/go/render"
+)
+
+// Invalidate marks the widget and its parents dirty, so they are computed
+// and drawn again on the next frame. The widget setters (SetBackground,
+// MoveTo, Resize, Show, ...) call it when the value changed; a custom widget
+// calls it when its own state changes how it looks.
+func (w *BaseWidget) Invalidate() {
+	w.clean = false
+	w.drawn.damaged = true
+	for parent, ok := w.Parent(); ok; parent, ok = parent.Parent() {
+		if d, ok := parent.(dirtier); ok {
+			d.setClean(false)
+		}
+	}
+}
+
+// invalidateMove marks the widget dirty after it was moved, like Invalidate,
+// but its area is only drawn again if it ends up somewhere else than where it
+// was drawn: Frames may move their children around while they lay them out.
+func (w *BaseWidget) invalidateMove() {
+	var damaged = w.drawn.damaged
+	w.Invalidate()
+	w.drawn.damaged = damaged
+	w.drawn.moved = true
+}
+
+// Dirty returns whether the widget changed since it was last drawn by a
+// program that only draws when something changed. A widget is dirty until
+// then, so Compute is never skipped by programs that redraw every frame.
+func (w *BaseWidget) Dirty() bool {
+	return !w.clean
+}
+
+// setClean sets the dirty flag without notifying the parents.
+func (w *BaseWidget) setClean(v bool) {
+	w.clean = v
+}
+
+// isClean returns the dirty flag, unlike Dirty which widgets may override.
+func (w *BaseWidget) isClean() bool {
+	return w.clean
+}
+
+// drawing returns where the widget was last drawn.
+func (w *BaseWidget) drawing() *drawState {
+	return &w.drawn
+}
+
+// drawState is where a widget was last drawn, so that only the area of the
+// widgets that changed needs to be drawn again (see Supervisor.Damage).
+type drawState struct {
+	damaged bool         // invalidated since it was drawn
+	moved   bool         // moved since it was drawn
+	known   bool         // whether it was drawn at all
+	rect    render.Rect  // where it was drawn
+	point   render.Point // its Point at the time, to follow it as it moves
+}
+
+// presented records where a widget was drawn.
+func (d *drawState) presented(w Widget, p render.Point) {
+	var size = w.Size()
+	d.known = true
+	d.rect = render.Rect{X: p.X, Y: p.Y, W: size.W, H: size.H}
+	d.point = w.Point()
+}
+
+// dirtier is a widget with a dirty flag, i.e. one based on BaseWidget.
+// Widgets may override Dirty to tell about changes that don't go through
+// Invalidate, like a Label whose TextVariable was changed.
+type dirtier interface {
+	Dirty() bool
+	Invalidate()
+	setClean(bool)
+	isClean() bool
+	drawing() *drawState
+}
+
+// isDirty returns whether a widget needs its Compute. Widgets without a
+// dirty flag always do.
+func isDirty(w Widget) bool {
+	if d, ok := w.(dirtier); ok {
+		return d.Dirty()
+	}
+	return true
+}
+
+// pollDirty visits the visible widgets of a tree and invalidates the ones
+// that are dirty without having called Invalidate, so their parents are
+// dirty too.
+func pollDirty(root Widget) {
+	walkVisible(root, func(w Widget) {
+		if d, ok := w.(dirtier); ok && d.isClean() && d.Dirty() {
+			d.Invalidate()
+		}
+	})
+}
+
+// markClean marks the visible widgets of a tree clean after it was drawn.
+// Hidden widgets stay dirty, to be computed again when they are shown, but
+// a hidden root is clean: it was drawn by not being drawn, and Show makes it
+// dirty again.
+func markClean(root Widget) {
+	walkDrawn(root, true, func(w Widget, visible bool) {
+		d, ok := w.(dirtier)
+		if !ok {
+			return
+		}
+		if visible || w == root {
+			d.setClean(true)
+		}
+		if !visible {
+			*d.drawing() = drawState{}
+		}
+		d.drawing().damaged = false
+		d.drawing().moved = false
+	})
+}
+
+// walkVisible calls fn for a widget and its visible descendants, children
+// before their parents.
+func walkVisible(w Widget, fn func(Widget)) {
+	if w == nil || w.Hidden() {
+		return
+	}
+	for _, child := range w.Children() {
+		walkVisible(child, fn)
+	}
+	fn(w)
+}
+
+// walkDrawn calls fn for a widget and all of its descendants, children before
+// their parents, telling whether each one is visible.
+func walkDrawn(w Widget, visible bool, fn func(w Widget, visible bool)) {
+	if w == nil {
+		return
+	}
+	visible = visible && !w.Hidden()
+	for _, child := range w.Children() {
+		walkDrawn(child, visible, fn)
+	}
+	fn(w, visible)
+}
+
+// NeedsRedraw returns whether anything changed since the last frame was
+// drawn, for programs that only draw when something changed instead of on
+// every frame. The roots are the widget trees the program draws itself, like
+// the MainWindow's frame; the Supervisor's windows, modals and DrawOnTop
+// widgets are checked too. Call it after Loop, and call Drawn after drawing
+// the frame:
+//
+//	supervisor.Loop(ev)
+//	if supervisor.NeedsRedraw(frame) {
+//		engine.Clear(render.White)
+//		frame.Compute(engine)
+//		supervisor.Compute(engine)
+//		frame.Present(engine, frame.Point())
+//		supervisor.Present(engine)
+//		engine.Present()
+//		supervisor.Drawn(frame)
+//	}
+//
+// A tree changed when any of its widgets is dirty (see BaseWidget.Invalidate
+// and Dirty), including the widgets bound to an observable value that was
+// set; the Supervisor's own changes, like a modal being pushed or a window
+// raised, are counted for it alone. Once the widgets were drawn, Frames skip
+// the Compute of their children that didn't change.
+func (s *Supervisor) NeedsRedraw(roots ...Widget) bool {
+	var dirty bool
+	for _, root := range s.drawnRoots(roots) {
+		pollDirty(root)
+		if isDirty(root) {
+			dirty = true
+		}
+	}
+
+	return dirty || !s.drawn || atomic.LoadUint64(&s.changes) != s.drawnAt
+}
+
+// Drawn tells the Supervisor that the roots were drawn after NeedsRedraw
+// returned true, so their widgets are clean until they change again. The
+// changes made while drawing, like a Frame moving its children into place,
+// don't count.
+func (s *Supervisor) Drawn(roots ...Widget) {
+	for _, root := range s.drawnRoots(roots) {
+		markClean(root)
+	}
+	s.drawnAt = atomic.LoadUint64(&s.changes)
+	s.drawn = true
+}
+
+// Damage returns the area to draw again after NeedsRedraw returned true, for
+// programs that draw only what changed: the area where the changed widgets
+// were drawn last, and where they will be drawn now. Call it after Compute
+// has moved the widgets into place, then present the roots and the
+// Supervisor through a ClipEngine clipped to the area.
+//
+// It returns false when everything needs to be drawn, e.g. on the first
+// frame, when a modal was pushed or a window raised, or when a widget
+// changed that wasn't drawn before nor inside a parent that was.
+func (s *Supervisor) Damage(roots ...Widget) (render.Rect, bool) {
+	if !s.drawn || atomic.LoadUint64(&s.changes) != s.drawnAt {
+		return render.Rect{}, false
+	}
+
+	var (
+		area  render.Rect
+		known = true
+	)
+	for _, root := range s.drawnRoots(roots) {
+		walkDrawn(root, true, func(w Widget, visible bool) {
+			d, ok := w.(dirtier)
+			if !ok {
+				return
+			}
+
+			var (
+				drawn       = d.drawing()
+				rect, found = drawnRect(w)
+			)
+			if !drawn.damaged && !(drawn.moved && (!visible || rect != drawn.rect)) {
+				return
+			}
+			if drawn.known {
+				area = unionRect(area, drawn.rect)
+			}
+			if visible {
+				area = unionRect(area, rect)
+				known = known && found
+			}
+		})
+	}
+	return area, known
+}
+
+// drawnRect returns where a widget will be drawn: where it was drawn last,
+// moved and resized like the widget was since. A widget that was not drawn
+// yet is inside of the rect of its parent, if that was.
+func drawnRect(w Widget) (render.Rect, bool) {
+	if d, ok := w.(dirtier); ok && d.drawing().known {
+		var (
+			drawn = d.drawing()
+			point = w.Point()
+			size  = w.Size()
+		)
+		return render.Rect{
+			X: drawn.rect.X + point.X - drawn.point.X,
+			Y: drawn.rect.Y + point.Y - drawn.point.Y,
+			W: size.W,
+			H: size.H,
+		}, true
+	}
+	if parent, ok := w.Parent(); ok {
+		return drawnRect(parent)
+	}
+	return render.Rect{}, false
+}
+
+// invalidate records a change of the Supervisor's windows, modals or
+// DrawOnTop widgets that needs them to be drawn again, see NeedsRedraw.
+func (s *Supervisor) invalidate() {
+	atomic.AddUint64(&s.changes, 1)
+	s.wakeUp()
+}
+
+// drawnRoots returns the roots with the widgets the Supervisor draws.
+func (s *Supervisor) drawnRoots(roots []Widget) []Widget {
+	roots = append([]Widget{}, roots...)
+	for item := s.winBottom; item != nil; item = item.prev {
+		roots = append(roots, item.window)
+	}
+
+	s.lock.RLock()
+	roots = append(roots, s.modals...)
+	roots = append(roots, s.onTop...)
+	s.lock.RUnlock()
+	return roots
+}
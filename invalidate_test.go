@@ -0,0 +1,192 @@
+package ui_test
+
+import (
+	"testing"
+
+	"git.kirsle.net/go/render"
+	"git.kirsle.net/go/render/event"
+	"git.kirsle.net/go/ui"
+	"git.kirsle.net/go/ui/softrender"
+)
+
+func TestNeedsRedraw(t *testing.T) {
+	defer emptyTextCache()()
+	var (
+		engine = &countingEngine{Engine: softrender.New(200, 200)}
+		s      = ui.NewSupervisor()
+		ev     = event.NewState()
+		frame  = ui.NewFrame("Root")
+		score  = 0
+		sound  = false
+		name   = ui.NewStringValue("Player 1")
+		drawn  int
+	)
+	frame.Resize(render.NewRect(200, 200))
+
+	title := ui.NewLabel(ui.Label{Text: "Title"})
+	frame.Pack(title, ui.Pack{Side: ui.N})
+	frame.Pack(ui.NewLabel(ui.Label{IntVariable: &score}), ui.Pack{Side: ui.N})
+	frame.Pack(ui.NewLabel(ui.Label{Binding: name}), ui.Pack{Side: ui.N})
+	check := ui.NewCheckbox("Sound", &sound, ui.NewLabel(ui.Label{Text: "Sound"}))
+	frame.Pack(check, ui.Pack{Side: ui.N})
+
+	// loop runs frames like the MainWindow, until nothing changes, and
+	// returns how many labels were measured.
+	loop := func() int {
+		engine.measured = 0
+		for i := 0; i < 5; i++ {
+			s.Loop(ev)
+			if !s.NeedsRedraw(frame) {
+				return engine.measured
+			}
+			frame.Compute(engine)
+			frame.Present(engine, frame.Point())
+			s.Present(engine)
+			s.Drawn(frame)
+			drawn++
+		}
+		t.Fatalf("the frames were still dirty after 5 loops")
+		return 0
+	}
+
+	if loop(); drawn == 0 {
+		t.Errorf("expected the first frame to be drawn")
+	}
+	drawn = 0
+	if loop(); drawn != 0 {
+		t.Errorf("expected no frames to be drawn when nothing changed, got %d", drawn)
+	}
+
+	// Only the changed labels are measured again.
+	for _, test := range []struct {
+		name   string
+		change func()
+		expect int
+	}{
+		{"text", func() { title.Text = "New title" }, 1},
+		{"pointer", func() { score = 10 }, 1},
+		{"binding", func() {
+			// Set from another goroutine.
+			done := make(chan struct{})
+			go func() {
+				name.Set("Player 2")
+				close(done)
+			}()
+			<-done
+		}, 1},
+		{"checkbox", func() { sound = true }, 0},
+		{"hide", func() { check.Hide() }, 0},
+	} {
+		test.change()
+
+		drawn = 0
+		measured := loop()
+		if drawn == 0 {
+			t.Errorf("%s: expected a frame to be drawn", test.name)
+		}
+		if measured != test.expect {
+			t.Errorf("%s: expected %d labels measured, got %d", test.name, test.expect, measured)
+		}
+	}
+}
+
+// The changes of one Supervisor's widgets don't redraw another's.
+func TestNeedsRedrawSupervisors(t *testing.T) {
+	var (
+		engine = softrender.New(100, 100)
+		ev     = event.NewState()
+		frames = []*ui.Frame{ui.NewFrame("One"), ui.NewFrame("Two")}
+		sups   = []*ui.Supervisor{ui.NewSupervisor(), ui.NewSupervisor()}
+		name   = ui.NewStringValue("Player 1")
+	)
+	frames[0].Pack(ui.NewLabel(ui.Label{Binding: name}), ui.Pack{Side: ui.N})
+	frames[1].Pack(ui.NewLabel(ui.Label{Text: "Title"}), ui.Pack{Side: ui.N})
+	for i, s := range sups {
+		frames[i].Resize(render.NewRect(100, 100))
+		s.Loop(ev)
+		if !s.NeedsRedraw(frames[i]) {
+			t.Fatalf("supervisor %d: expected the first frame to be drawn", i)
+		}
+		frames[i].Compute(engine)
+		frames[i].Present(engine, frames[i].Point())
+		s.Drawn(frames[i])
+	}
+
+	name.Set("Player 2")
+	sups[1].PushModal(ui.NewFrame("Modal"))
+	for i, s := range sups {
+		s.Loop(ev)
+		if !s.NeedsRedraw(frames[i]) {
+			t.Errorf("supervisor %d: expected its change to be drawn", i)
+		}
+		s.Drawn(frames[i])
+	}
+
+	// Each one's change is drawn by itself only.
+	name.Set("Player 3")
+	if sups[1].NeedsRedraw(frames[1]) {
+		t.Error("expected the other supervisor's label not to redraw this one")
+	}
+	if !sups[0].NeedsRedraw(frames[0]) {
+		t.Error("expected the bound label to be redrawn")
+	}
+}
+
+// Drawing only the Damage area after a change looks the same as drawing the
+// whole frame again.
+func TestDamage(t *testing.T) {
+	var (
+		engine = softrender.New(200, 100)
+		s      = ui.NewSupervisor()
+		ev     = event.NewState()
+		frame  = ui.NewFrame("Root")
+		title  = testLabel("Title")
+		score  = testLabel("Score: 0")
+	)
+	frame.Resize(render.NewRect(200, 100))
+	frame.SetBackground(testBackground)
+	frame.Pack(title, ui.Pack{Side: ui.N})
+	frame.Pack(score, ui.Pack{Side: ui.N})
+
+	draw := func(e render.Engine) {
+		frame.Compute(engine)
+		frame.Present(e, frame.Point())
+		s.Present(e)
+	}
+
+	s.Loop(ev)
+	if !s.NeedsRedraw(frame) {
+		t.Fatal("expected the first frame to be drawn")
+	}
+	if _, ok := s.Damage(frame); ok {
+		t.Error("expected the first frame to be drawn in full")
+	}
+	draw(engine)
+	s.Drawn(frame)
+
+	score.Text = "Score: 100"
+	s.Loop(ev)
+	if !s.NeedsRedraw(frame) {
+		t.Fatal("expected the changed label to be drawn")
+	}
+	frame.Compute(engine)
+	damage, ok := s.Damage(frame)
+	if !ok {
+		t.Fatal("expected only the changed label to be drawn")
+	}
+	if top := ui.AbsoluteRect(title); damage.Y < top.Y+top.H || damage.H == 0 {
+		t.Errorf("expected the damage at Y=%d H=%d to be below the title at Y=%d H=%d", damage.Y, damage.H, top.Y, top.H)
+	}
+
+	clip := ui.Clip(engine)
+	clip.PushClip(damage)
+	draw(clip)
+	clip.PopClip()
+	s.Drawn(frame)
+
+	full := softrender.New(200, 100)
+	draw(full)
+	if string(engine.Image().Pix) != string(full.Image().Pix) {
+		t.Error("expected the redrawn damage to look like the whole frame drawn again")
+	}
+}
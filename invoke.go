@@ -0,0 +1,81 @@
+package ui
+
+import "time"
+
+/*
+invoke.go holds the Supervisor's queue of tasks to run on the UI goroutine.
+
+Widgets are not safe to change from other goroutines. A goroutine that has
+finished some background work (e.g. loading an image) posts a function with
+Invoke, and the Supervisor runs it at the start of its next Loop, on the
+goroutine that runs the UI.
+*/
+
+// Invoke queues a function to be run on the UI goroutine at the start of the
+// next Supervisor.Loop. It is safe to call from any goroutine, and returns
+// right away.
+func (s *Supervisor) Invoke(fn func()) {
+	if fn == nil {
+		return
+	}
+
+	s.taskLock.Lock()
+	s.tasks = append(s.tasks, fn)
+	s.taskLock.Unlock()
+	s.wakeUp()
+}
+
+// RunOnUIThread queues a function like Invoke and waits until it has been
+// run by the Supervisor's Loop.
+//
+// Do not call it from the UI goroutine itself (e.g. from an event handler):
+// it would wait forever for a Loop that can't run until it returns. Use
+// Invoke there instead.
+func (s *Supervisor) RunOnUIThread(fn func()) {
+	if fn == nil {
+		return
+	}
+
+	done := make(chan struct{})
+	s.Invoke(func() {
+		defer close(done)
+		fn()
+	})
+	<-done
+}
+
+// runTasks is a subroutine of Supervisor.Loop that runs the queued tasks.
+// Tasks queued while these run will wait for the next Loop.
+func (s *Supervisor) runTasks() {
+	s.taskLock.Lock()
+	tasks := s.tasks
+	s.tasks = nil
+	s.taskLock.Unlock()
+
+	for _, fn := range tasks {
+		fn()
+	}
+}
+
+// wait sleeps until the next frame is due, or until a task was invoked or
+// the Supervisor changed, for a main loop that idles between frames.
+func (s *Supervisor) wait(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+
+	var timer = time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-s.wake:
+	case <-timer.C:
+	}
+}
+
+// wakeUp ends a wait early. It is safe to call from any goroutine.
+func (s *Supervisor) wakeUp() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,105 @@
+package ui
+
+import (
+	"sync"
+	"testing"
+
+	"git.kirsle.net/go/render"
+	"git.kirsle.net/go/render/event"
+)
+
+func TestInvoke(t *testing.T) {
+	var (
+		s   = NewSupervisor()
+		ev  = event.NewState()
+		ran = []int{}
+		wg  sync.WaitGroup
+	)
+
+	// Tasks queued from other goroutines wait for the next Loop.
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.Invoke(func() {
+				ran = append(ran, len(ran))
+			})
+		}()
+	}
+	wg.Wait()
+	if len(ran) != 0 {
+		t.Fatalf("tasks ran before Loop: %v", ran)
+	}
+
+	s.Loop(ev)
+	if len(ran) != 3 {
+		t.Fatalf("expected 3 tasks to run in Loop, got %d", len(ran))
+	}
+
+	// RunOnUIThread blocks until a Loop has run its task.
+	var (
+		done   = make(chan struct{})
+		called bool
+	)
+	go func() {
+		s.RunOnUIThread(func() {
+			called = true
+		})
+		close(done)
+	}()
+
+	for {
+		s.Loop(ev)
+		select {
+		case <-done:
+			if !called {
+				t.Errorf("RunOnUIThread returned before its task ran")
+			}
+			return
+		default:
+		}
+	}
+}
+
+// Other goroutines may read the Supervisor's state while the UI goroutine
+// changes it. Run with -race.
+func TestSupervisorReaders(t *testing.T) {
+	var (
+		s       = NewSupervisor()
+		ev      = event.NewState()
+		btn     = NewButton("Button", NewLabel(Label{Text: "Hello"}))
+		stop    = make(chan struct{})
+		done    = make(chan struct{})
+		started = make(chan struct{})
+	)
+	btn.Resize(render.NewRect(100, 20))
+	s.Add(btn)
+
+	go func() {
+		defer close(done)
+		close(started)
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				s.FocusedWidget()
+				s.GetModal()
+				s.hitWidgets(render.NewPoint(10, 10))
+			}
+		}
+	}()
+
+	<-started
+	for i := 0; i < 200; i++ {
+		ev.CursorX, ev.CursorY = 10+i%2*200, 10
+		ev.Button1 = i%4 < 2
+		s.Loop(ev)
+		s.PushModal(btn)
+		s.PopModal(btn)
+		s.Focus(btn)
+	}
+	s.Remove(btn)
+	close(stop)
+	<-done
+}
@@ -0,0 +1,345 @@
+package ui
+
+import (
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"git.kirsle.net/go/render/event"
+)
+
+/*
+keys.go holds the Supervisor methods that turn the keyboard state from the
+render engine into KeyDown, KeyUp and KeyPress events for the focused widget.
+*/
+
+// Key repeat timings for KeyPress events while a key is held down.
+var (
+	KeyRepeatDelay    = 500 * time.Millisecond // delay before the first repeat
+	KeyRepeatInterval = 50 * time.Millisecond  // delay between each repeat
+)
+
+// Key names recognized by the ui package. Printable keys are named by the
+// character they type (e.g. "a", "1", "/").
+const (
+	KeyTab        = "Tab"
+	KeyEnter      = "Enter"
+	KeyEscape     = "Escape"
+	KeyBackspace  = "Backspace"
+	KeyDelete     = "Delete"
+	KeySpace      = "Space"
+	KeyArrowUp    = "Up"
+	KeyArrowDown  = "Down"
+	KeyArrowLeft  = "Left"
+	KeyArrowRight = "Right"
+	KeyHome       = "Home"
+	KeyEnd        = "End"
+	KeyPageUp     = "PageUp"
+	KeyPageDown   = "PageDown"
+)
+
+// keyAliases maps the ui package's key names to the names that the render
+// engines may use for the same key in event.State.
+var keyAliases = map[string][]string{
+	KeyTab:        {"Tab", "\t"},
+	KeyEnter:      {"Enter", "Return", "\n", "\r"},
+	KeyEscape:     {"Escape", "\x1b"},
+	KeyBackspace:  {"Backspace", "\b"},
+	KeyDelete:     {"Delete", "\x7f"},
+	KeySpace:      {"Space", " "},
+	KeyArrowUp:    {"Up", "ArrowUp"},
+	KeyArrowDown:  {"Down", "ArrowDown"},
+	KeyArrowLeft:  {"Left", "ArrowLeft"},
+	KeyArrowRight: {"Right", "ArrowRight"},
+	KeyHome:       {"Home"},
+	KeyEnd:        {"End"},
+	KeyPageUp:     {"PageUp"},
+	KeyPageDown:   {"PageDown"},
+}
+
+// modifierKeys are not reported as keys of their own; their state is
+// carried in the Shift, Ctrl and Alt fields of EventData instead.
+var modifierKeys = map[string]interface{}{
+	"Shift":  nil,
+	"LShift": nil,
+	"RShift": nil,
+	"Ctrl":   nil,
+	"LCtrl":  nil,
+	"RCtrl":  nil,
+	"Alt":    nil,
+	"LAlt":   nil,
+	"RAlt":   nil,
+}
+
+// shiftedKeys maps printable keys to the character they type when Shift is
+// held, for a US keyboard layout.
+var shiftedKeys = map[rune]rune{
+	'1': '!', '2': '@', '3': '#', '4': '$', '5': '%',
+	'6': '^', '7': '&', '8': '*', '9': '(', '0': ')',
+	'-': '_', '=': '+', '[': '{', ']': '}', '\\': '|',
+	';': ':', '\'': '"', ',': '<', '.': '>', '/': '?',
+	'`': '~',
+}
+
+// keyState tracks a key that is being held down, for auto-repeat.
+type keyState struct {
+	nextRepeat time.Time
+}
+
+// normalizeKey returns the ui package's name for a key as named by the
+// render engine.
+func normalizeKey(name string) string {
+	for key, aliases := range keyAliases {
+		for _, alias := range aliases {
+			if name == alias {
+				return key
+			}
+		}
+	}
+
+	// Single printable characters are named by their lowercase form.
+	if utf8.RuneCountInString(name) == 1 {
+		return strings.ToLower(name)
+	}
+
+	return name
+}
+
+// keyRune returns the printable character typed by a key, or zero if the
+// key does not type a character.
+func keyRune(name string, shift bool) rune {
+	if name == KeySpace {
+		return ' '
+	}
+
+	if utf8.RuneCountInString(name) != 1 {
+		return 0
+	}
+
+	r, _ := utf8.DecodeRuneInString(name)
+	if !unicode.IsPrint(r) {
+		return 0
+	}
+
+	if shift {
+		if shifted, ok := shiftedKeys[r]; ok {
+			return shifted
+		}
+		return unicode.ToUpper(r)
+	}
+	return r
+}
+
+// RuneKey returns the name of the key that types a character, and whether
+// Shift must be held for it, for a US keyboard layout. It is the reverse of
+// the Rune of KeyPress events. Returns an empty key name if no key types it.
+func RuneKey(r rune) (key string, shift bool) {
+	switch r {
+	case ' ':
+		return KeySpace, false
+	case '\n', '\r':
+		return KeyEnter, false
+	case '\t':
+		return KeyTab, false
+	}
+
+	for plain, shifted := range shiftedKeys {
+		if r == shifted {
+			return string(plain), true
+		}
+	}
+
+	if !unicode.IsPrint(r) {
+		return "", false
+	}
+	if unicode.IsUpper(r) {
+		return string(unicode.ToLower(r)), true
+	}
+	return string(r), false
+}
+
+// keysDown returns the sorted names of all keys currently pressed in the
+// event state, not including the modifier keys.
+func keysDown(ev *event.State) []string {
+	var (
+		seen  = map[string]interface{}{}
+		names = []string{}
+	)
+
+	add := func(name string) {
+		if _, ok := modifierKeys[name]; ok {
+			return
+		}
+		if _, ok := seen[name]; !ok {
+			seen[name] = nil
+			names = append(names, name)
+		}
+	}
+
+	// Keys that the render engine reports with dedicated fields.
+	for _, special := range []struct {
+		Name string
+		Down bool
+	}{
+		{KeyEscape, ev.Escape},
+		{KeyEnter, ev.Enter},
+		{KeyArrowUp, ev.Up},
+		{KeyArrowDown, ev.Down},
+		{KeyArrowLeft, ev.Left},
+		{KeyArrowRight, ev.Right},
+	} {
+		if special.Down {
+			add(special.Name)
+		}
+	}
+
+	for _, name := range ev.KeysDown(false) {
+		add(normalizeKey(name))
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// loopKeys is a subroutine of Supervisor.Loop that sends keyboard events.
+//
+// A KeyDown event is sent when a key is first pressed, followed by KeyPress
+// events: one right away, then repeated while the key is held down (see
+// KeyRepeatDelay and KeyRepeatInterval). A KeyUp event is sent when the
+// key is released.
+//
+// The Tab key moves the keyboard focus and registered shortcut keys run their
+// commands, unless the focused widget's KeyDown handler returns
+// ErrStopPropagation to keep the key for itself.
+func (s *Supervisor) loopKeys(ev *event.State) {
+	var (
+		now     = s.tick
+		pressed = map[string]interface{}{}
+	)
+
+	for _, key := range keysDown(ev) {
+		pressed[key] = nil
+
+		ed := EventData{
+			Supervisor: s,
+			Key:        key,
+			Rune:       keyRune(key, ev.Shift),
+			Shift:      ev.Shift,
+			Ctrl:       ev.Ctrl,
+			Alt:        ev.Alt,
+		}
+
+		state, ok := s.keysDown[key]
+		if !ok {
+			// Newly pressed key.
+			s.keysDown[key] = &keyState{
+				nextRepeat: now.Add(KeyRepeatDelay),
+			}
+
+			err := s.sendKeyEvent(KeyDown, ed)
+			if err != ErrStopPropagation {
+				// Tab to move the focus.
+				if key == KeyTab {
+					if ev.Shift {
+						s.FocusPrev()
+					} else {
+						s.FocusNext()
+					}
+					continue
+				}
+
+				// Keyboard shortcuts consume the key press.
+				if s.runShortcut(ed) {
+					continue
+				}
+			}
+
+			s.sendKeyEvent(KeyPress, ed)
+		} else if !now.Before(state.nextRepeat) {
+			// Held down long enough to auto-repeat.
+			state.nextRepeat = now.Add(KeyRepeatInterval)
+			if key == KeyTab {
+				continue
+			}
+
+			ed.Repeat = true
+			s.sendKeyEvent(KeyPress, ed)
+		}
+	}
+
+	// Keys that have been released.
+	var released = []string{}
+	for key := range s.keysDown {
+		if _, ok := pressed[key]; !ok {
+			released = append(released, key)
+		}
+	}
+	sort.Strings(released)
+	for _, key := range released {
+		delete(s.keysDown, key)
+		s.sendKeyEvent(KeyUp, EventData{
+			Supervisor: s,
+			Key:        key,
+			Rune:       keyRune(key, ev.Shift),
+			Shift:      ev.Shift,
+			Ctrl:       ev.Ctrl,
+			Alt:        ev.Alt,
+		})
+	}
+}
+
+// sendKeyEvent delivers a keyboard event.
+//
+// The event goes to the focused widget first. If it has no handler for the
+// event, it falls back to the widget's parents in turn, and lastly to the
+// focused Window (or the active modal, if there is one). The error from the
+// first widget that handled the event is returned, or ErrNoEventHandler.
+//
+// Returning ErrStopPropagation from a KeyDown handler tells the Supervisor
+// not to apply its own default action for the key (e.g. Tab to move focus).
+func (s *Supervisor) sendKeyEvent(e Event, ed EventData) error {
+	var visited = map[Widget]interface{}{}
+
+	send := func(w Widget) (bool, error) {
+		if w == nil || w.Hidden() {
+			return false, ErrNoEventHandler
+		}
+		if _, ok := visited[w]; ok {
+			return false, ErrNoEventHandler
+		}
+		visited[w] = nil
+
+		// Only widgets with a handler for this event will accept it.
+		if h, ok := w.(interface{ hasHandler(Event) bool }); ok && !h.hasHandler(e) {
+			return false, ErrNoEventHandler
+		}
+
+		ed.Widget = w
+		return true, w.Event(e, ed)
+	}
+
+	// The focused widget and its ancestors.
+	var node = s.FocusedWidget()
+	for node != nil {
+		if handled, err := send(node); handled {
+			return err
+		}
+
+		parent, ok := node.Parent()
+		if !ok {
+			break
+		}
+		node = parent
+	}
+
+	// The scope that owns the keyboard: the top modal or focused window.
+	if scope := s.focusScope(); scope != nil {
+		if handled, err := send(scope); handled {
+			return err
+		}
+	}
+
+	return ErrNoEventHandler
+}
@@ -0,0 +1,116 @@
+package ui
+
+import (
+	"testing"
+
+	"git.kirsle.net/go/render/event"
+)
+
+func TestKeyEvents(t *testing.T) {
+	var (
+		s   = NewSupervisor()
+		btn = NewButton("Button", NewLabel(Label{Text: "Hello"}))
+		log = []string{}
+	)
+	s.Add(btn)
+
+	for _, e := range []Event{KeyDown, KeyPress, KeyUp} {
+		e := e
+		btn.Handle(e, func(ed EventData) error {
+			log = append(log, ed.Key+string(ed.Rune))
+			return nil
+		})
+	}
+
+	if err := s.Focus(btn); err != nil {
+		t.Fatalf("Focus: %s", err)
+	}
+
+	ev := event.NewState()
+	ev.Shift = true
+	ev.SetKeyDown("a", true)
+	s.Loop(ev)
+	s.Loop(ev) // held down: no repeat before KeyRepeatDelay
+	ev.SetKeyDown("a", false)
+	s.Loop(ev)
+
+	expect := []string{"aA", "aA", "aA"}
+	if len(log) != len(expect) {
+		t.Fatalf("expected %d key events, got %d: %+v", len(expect), len(log), log)
+	}
+	for i := range expect {
+		if log[i] != expect[i] {
+			t.Errorf("event %d: expected %q, got %q", i, expect[i], log[i])
+		}
+	}
+}
+
+func TestKeyFallback(t *testing.T) {
+	var (
+		s      = NewSupervisor()
+		frame  = NewFrame("Parent")
+		btn    = NewButton("Button", NewLabel(Label{Text: "Hello"}))
+		target Widget
+	)
+	frame.Pack(btn)
+	s.Add(btn)
+	s.Focus(btn)
+
+	// The button has no KeyDown handler; the event falls back to its parent.
+	frame.Handle(KeyDown, func(ed EventData) error {
+		target = ed.Widget
+		return nil
+	})
+
+	ev := event.NewState()
+	ev.Enter = true
+	s.Loop(ev)
+
+	if target != frame {
+		t.Errorf("expected KeyDown to fall back to the parent frame, got %v", target)
+	}
+}
+
+func TestTabFocus(t *testing.T) {
+	var (
+		s     = NewSupervisor()
+		frame = NewFrame("Parent")
+		btn1  = NewButton("Button1", NewLabel(Label{Text: "One"}))
+		btn2  = NewButton("Button2", NewLabel(Label{Text: "Two"}))
+	)
+	frame.Pack(btn1)
+	frame.Pack(btn2)
+	s.Add(btn2)
+	s.Add(btn1)
+
+	tab := func(shift bool) {
+		ev := event.NewState()
+		ev.Shift = shift
+		ev.SetKeyDown("\t", true)
+		s.Loop(ev)
+		ev.SetKeyDown("\t", false)
+		s.Loop(ev)
+	}
+
+	// Tree order, not the order they were supervised.
+	for i, expect := range []Widget{btn1, btn2, btn1} {
+		tab(false)
+		if s.FocusedWidget() != expect {
+			t.Errorf("tab %d: expected focus on %s, got %v", i, expect, s.FocusedWidget())
+		}
+	}
+
+	tab(true)
+	if s.FocusedWidget() != btn2 {
+		t.Errorf("shift+tab: expected focus on %s, got %v", btn2, s.FocusedWidget())
+	}
+}
+
+func TestRuneKey(t *testing.T) {
+	for _, r := range "aZ1!? ~{" {
+		key, shift := RuneKey(r)
+		if got := keyRune(key, shift); got != r {
+			t.Errorf("RuneKey(%q) = %q, %v: types %q", r, key, shift, got)
+		}
+	}
+}
@@ -22,12 +22,18 @@ type Label struct {
 	Text         string
 	TextVariable *string
 	IntVariable  *int
+	Binding      Binding // an observable value, e.g. a *StringValue
 	Font         render.Text
 
 	style      *style.Label
 	width      int
 	height     int
 	lineHeight int
+
+	// A bound label is only measured again when its value or font changed.
+	bound        boundValue
+	measured     bool
+	measuredFont render.Text
 }
 
 // NewLabel creates a new label.
@@ -36,6 +42,7 @@ func NewLabel(c Label) *Label {
 		Text:         c.Text,
 		TextVariable: c.TextVariable,
 		IntVariable:  c.IntVariable,
+		Binding:      c.Binding,
 		Font:         DefaultFont,
 	}
 	w.SetStyle(Theme.Label)
@@ -59,10 +66,13 @@ func (w *Label) SetStyle(v *style.Label) {
 	w.Font.Color = w.style.Foreground
 }
 
-// text returns the label's displayed text, coming from the TextVariable if
-// available or else the Text attribute instead.
+// text returns the label's displayed text, coming from the Binding or the
+// TextVariable if available or else the Text attribute instead.
 func (w *Label) text() render.Text {
-	if w.TextVariable != nil {
+	if w.Binding != nil {
+		w.Font.Text = w.Binding.String()
+		return w.Font
+	} else if w.TextVariable != nil {
 		w.Font.Text = *w.TextVariable
 		return w.Font
 	} else if w.IntVariable != nil {
@@ -81,7 +91,31 @@ func (w *Label) Value() string {
 
 // Compute the size of the label widget.
 func (w *Label) Compute(e render.Engine) {
+	if w.bound.changed(w.Binding) || !w.measured || w.Font != w.measuredFont || w.Binding == nil {
+		w.measure(e)
+	}
+
+	// Call the BaseWidget Compute in case we have subscribers.
+	w.BaseWidget.Compute(e)
+}
+
+// Dirty returns whether the label changed since it was last drawn,
+// including changes to its Text, TextVariable, Binding or Font.
+func (w *Label) Dirty() bool {
+	return w.BaseWidget.Dirty() || !w.measured || w.text() != w.measuredFont
+}
+
+// Destroy unsubscribes the label from its Binding.
+func (w *Label) Destroy() {
+	w.bound.release()
+}
+
+// measure the size of the label's text.
+func (w *Label) measure(e render.Engine) {
 	text := w.text()
+	w.measured = true
+	w.measuredFont = text
+
 	lines := strings.Split(text.Text, "\n")
 
 	// Max rect to encompass all lines of text.
@@ -92,7 +126,7 @@ func (w *Label) Compute(e render.Engine) {
 		}
 
 		text.Text = line // only this line at this time.
-		rect, err := e.ComputeTextRect(text)
+		rect, err := DefaultTextCache.ComputeTextRect(e, text)
 		if err != nil {
 			panic(fmt.Sprintf("%s: failed to compute text rect: %s", w, err)) // TODO return an error
 		}
@@ -115,9 +149,6 @@ func (w *Label) Compute(e render.Engine) {
 			H: maxRect.H + (padY * 2),
 		})
 	}
-
-	// Call the BaseWidget Compute in case we have subscribers.
-	w.BaseWidget.Compute(e)
 }
 
 // Present the label widget.
@@ -137,7 +168,7 @@ func (w *Label) Present(e render.Engine, P render.Point) {
 	w.DrawBox(e, P)
 	for i, line := range strings.Split(text.Text, "\n") {
 		text.Text = line
-		e.DrawText(text, render.Point{
+		DefaultTextCache.DrawText(e, text, render.Point{
 			X: P.X + border + padX,
 			Y: P.Y + border + padY + (i * w.lineHeight),
 		})
@@ -0,0 +1,219 @@
+package ui
+
+/*
+lifecycle.go holds the teardown of widgets: removing them from the Supervisor
+and freeing their resources (such as textures) with Destroy.
+*/
+
+// Remove a widget from the Supervisor, so it no longer receives events.
+// Returns false if the widget was not under the supervisor's care.
+//
+// Any state the Supervisor kept about the widget is cleared: hover and click
+// states, the keyboard focus, the modal stack, DrawOnTop widgets, keyboard
+// shortcuts it owned and, for a Window, the window manager's focus list.
+//
+// Remove does not affect the widget's children; see RemoveTree. Like the
+// other methods that send events (here FocusOut and FocusIn), call it on
+// the UI goroutine, e.g. with Invoke.
+func (s *Supervisor) Remove(w Widget) bool {
+	return s.remove(map[Widget]interface{}{
+		w: nil,
+	}) > 0
+}
+
+// RemoveTree removes a widget and all of its descendants from the
+// Supervisor. Returns the number of widgets that had been supervised.
+//
+// Widgets owned by one in the tree are removed too, such as the popup Menu
+// of a MenuButton and the Tooltips attached to the widgets.
+func (s *Supervisor) RemoveTree(w Widget) int {
+	var tree = map[Widget]interface{}{}
+	walkTree(w, func(node Widget) {
+		tree[node] = nil
+	})
+	return s.remove(tree)
+}
+
+// remove is the implementation of Remove and RemoveTree.
+func (s *Supervisor) remove(widgets map[Widget]interface{}) int {
+	var (
+		removed int
+		ids     = []int{}
+	)
+
+	has := func(w Widget) bool {
+		if w == nil {
+			return false
+		}
+		_, ok := widgets[w]
+		return ok
+	}
+
+	s.lock.Lock()
+
+	// Tooltips go with their target widget.
+	for _, w := range s.onTop {
+		if tt, ok := w.(*Tooltip); ok && has(tt.target) {
+			widgets[tt] = nil
+		}
+	}
+
+	var keep = make([]WidgetSlot, 0, len(s.widgets))
+	for _, slot := range s.widgets {
+		if has(slot.widget) {
+			ids = append(ids, slot.id)
+		} else {
+			keep = append(keep, slot)
+		}
+	}
+	s.widgets = keep
+	for _, id := range ids {
+		delete(s.hovering, id)
+		delete(s.clicked, id)
+		removed++
+	}
+	for w := range widgets {
+		delete(s.ids, w)
+	}
+	s.hitIndex.dirty = true
+
+	// Widgets drawn on top.
+	var onTop = []Widget{}
+	for _, w := range s.onTop {
+		if !has(w) {
+			onTop = append(onTop, w)
+		}
+	}
+	s.onTop = onTop
+	s.invalidate()
+
+	// Modals below the top one are spliced out; the top one is popped below.
+	var popTop Widget
+	for i := len(s.modals) - 1; i >= 0; i-- {
+		if !has(s.modals[i]) {
+			continue
+		}
+
+		if i == len(s.modals)-1 {
+			popTop = s.modals[i]
+		} else {
+			s.modals = append(s.modals[:i], s.modals[i+1:]...)
+			if i < len(s.modalFocus) {
+				s.modalFocus = append(s.modalFocus[:i], s.modalFocus[i+1:]...)
+			}
+		}
+	}
+	for i, w := range s.modalFocus {
+		if has(w) {
+			s.modalFocus[i] = nil
+		}
+	}
+	s.lock.Unlock()
+
+	// Popping the top modal restores the keyboard focus from before.
+	if popTop != nil {
+		s.PopModal(popTop)
+	}
+
+	// Keyboard focus and shortcuts.
+	if has(s.FocusedWidget()) {
+		s.Blur()
+	}
+	s.lock.Lock()
+	var shortcuts = []*Shortcut{}
+	for _, sc := range s.shortcuts {
+		if !has(sc.owner) && !(sc.Scope != nil && has(sc.Scope)) {
+			shortcuts = append(shortcuts, sc)
+		}
+	}
+	s.shortcuts = shortcuts
+	s.lock.Unlock()
+
+	// Propagating mouse event state.
+	if has(s.hoverTarget) {
+		s.hoverTarget = nil
+	}
+	for button, target := range s.downTarget {
+		if has(target) {
+			s.downTarget[button] = nil
+		}
+	}
+
+	// Drag and drop: a drag from a removed widget is cancelled without
+	// sending it events.
+	if over, _ := s.dd.Over(); has(over) {
+		s.dd.over, s.dd.accepted = nil, false
+	}
+	if has(s.dd.Widget()) || has(s.dd.Ghost()) {
+		s.dd.SetWidget(nil)
+		s.DragStop()
+	}
+
+	// Managed windows.
+	for w := range widgets {
+		if window, ok := w.(*Window); ok && window.managed {
+			s.removeWindow(window)
+		}
+	}
+
+	return removed
+}
+
+// DestroyTree calls Destroy on a widget and all of its descendants, from
+// the bottom up, to free their resources such as the textures of Images.
+// Widgets whose Destroy only hides them, like the ColorPicker, free their
+// resources for good too.
+//
+// Widgets owned by one in the tree are destroyed too, such as the popup
+// Menu of a MenuButton. Destroyed widgets should not be used again; remove
+// them from the Supervisor first with RemoveTree.
+func DestroyTree(w Widget) {
+	var nodes = []Widget{}
+	walkTree(w, func(node Widget) {
+		nodes = append(nodes, node)
+	})
+
+	// walkTree visits parents before their children: destroy in reverse.
+	for i := len(nodes) - 1; i >= 0; i-- {
+		nodes[i].Destroy()
+		if t, ok := nodes[i].(tearer); ok {
+			t.teardown()
+		}
+	}
+}
+
+// tearer is a widget with resources that only DestroyTree frees, because
+// its Destroy method is expected to keep it usable.
+type tearer interface {
+	teardown()
+}
+
+// walkTree calls a function for a widget and each of its descendants, and
+// the widgets that they own (such as a MenuButton's popup Menu), parents
+// first.
+func walkTree(w Widget, fn func(Widget)) {
+	var (
+		seen  = map[Widget]interface{}{}
+		crawl func(Widget)
+	)
+	crawl = func(node Widget) {
+		if node == nil {
+			return
+		}
+		if _, ok := seen[node]; ok {
+			return
+		}
+		seen[node] = nil
+		fn(node)
+
+		for _, child := range node.Children() {
+			crawl(child)
+		}
+		if owner, ok := node.(interface{ ownedWidgets() []Widget }); ok {
+			for _, child := range owner.ownedWidgets() {
+				crawl(child)
+			}
+		}
+	}
+	crawl(w)
+}
@@ -0,0 +1,105 @@
+package ui
+
+import (
+	"fmt"
+	"testing"
+
+	"git.kirsle.net/go/render"
+)
+
+func TestRemoveTree(t *testing.T) {
+	var (
+		s     = NewSupervisor()
+		frame = NewFrame("Screen")
+		btn   = NewButton("Button", NewLabel(Label{Text: "Hello"}))
+		other = NewButton("Other", NewLabel(Label{Text: "World"}))
+	)
+	frame.Pack(btn)
+	s.Add(frame)
+	s.Add(btn)
+	s.Add(other)
+	s.Focus(btn)
+
+	if n := s.RemoveTree(frame); n != 2 {
+		t.Errorf("expected to remove 2 widgets, removed %d", n)
+	}
+	if s.FocusedWidget() != nil {
+		t.Errorf("expected the removed button to lose focus")
+	}
+
+	var remaining = []Widget{}
+	for child := range s.Widgets() {
+		remaining = append(remaining, child.widget)
+	}
+	if len(remaining) != 1 || remaining[0] != other {
+		t.Errorf("expected only the other button to remain, got %v", remaining)
+	}
+
+	if s.Remove(btn) {
+		t.Errorf("expected Remove to return false for an unsupervised widget")
+	}
+}
+
+func TestRemoveWindow(t *testing.T) {
+	var (
+		s      = NewSupervisor()
+		bottom = NewWindow("Bottom")
+		top    = NewWindow("Top")
+	)
+	bottom.Supervise(s)
+	top.Supervise(s)
+
+	if !top.Focused() || bottom.Focused() {
+		t.Fatalf("expected the top window to be focused")
+	}
+
+	s.RemoveTree(top)
+	if !bottom.Focused() {
+		t.Errorf("expected the bottom window to be focused after removing the top")
+	}
+	if s.winFocus == nil || s.winFocus.window != bottom || s.winFocus.next != nil {
+		t.Errorf("expected only the bottom window in the focus list")
+	}
+}
+
+// textEngine is just enough of a render.Engine to compute labels.
+type textEngine struct {
+	render.Engine
+}
+
+func (textEngine) ComputeTextRect(text render.Text) (render.Rect, error) {
+	return render.NewRect(len(text.Text)*8, 16), nil
+}
+
+// DestroyTree unsubscribes the widgets from their bound values, so the
+// values don't keep them alive.
+func TestDestroyTreeBindings(t *testing.T) {
+	var (
+		engine   = textEngine{}
+		frame    = NewFrame("Screen")
+		name     = NewStringValue("Player 1")
+		sound    = NewBoolValue(true)
+		selected = NewValue("a")
+		label    = NewLabel(Label{Binding: name})
+		check    = NewCheckButtonValue("Sound", sound, NewLabel(Label{Text: "Sound"}))
+		list     = NewListBox("List", ListBox{Binding: selected})
+	)
+	frame.Pack(label)
+	frame.Pack(check)
+	frame.Pack(list)
+	label.Compute(engine)
+	check.Compute(engine)
+	list.Compute(engine)
+
+	subscribers := func() []int {
+		return []int{len(name.fns), len(sound.fns), len(selected.fns)}
+	}
+	if got := fmt.Sprint(subscribers()); got != "[1 1 1]" {
+		t.Fatalf("expected the widgets to subscribe to their values, got %s", got)
+	}
+
+	DestroyTree(frame)
+	if got := fmt.Sprint(subscribers()); got != "[0 0 0]" {
+		t.Errorf("expected DestroyTree to unsubscribe the widgets, got %s", got)
+	}
+}
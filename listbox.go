@@ -19,11 +19,22 @@ type ListBox struct {
 	scrollbar      *ScrollBar
 	scrollFraction float64
 	maxHeight      int
+	hoverRow       *ListValue // row under the mouse cursor
 
 	// Variable bindings: give these pointers to your values.
 	Variable interface{} // pointer to e.g. a string or int
 	// TextVariable *string // string value
 	// IntVariable  *int    // integer value
+
+	// Binding is an observable value for the selected item. It is updated
+	// when the user clicks an item, and the selection follows it when it
+	// is changed elsewhere.
+	Binding *Value
+	bound   boundValue
+
+	// Step is the number of pixels the list scrolls by for each notch of
+	// the mouse wheel, 1 by default.
+	Step int
 }
 
 // ListValue is an item in the ListBox. It has an arbitrary widget as a
@@ -42,11 +53,17 @@ func NewListBox(name string, config ListBox) *ListBox {
 		name:     name,
 		children: []*ListValue{},
 		Variable: config.Variable,
+		Binding:  config.Binding,
+		Step:     config.Step,
 		// TextVariable: config.TextVariable,
 		// IntVariable:  config.IntVariable,
 		style: &style.DefaultListBox,
 	}
 
+	if w.Binding != nil {
+		w.Variable = w.Binding.Get()
+	}
+
 	// if config.Width > 0 && config.Height > 0 {
 	// 	w.Frame.Resize(render.NewRect(config.Width, config.Height))
 	// }
@@ -54,6 +71,7 @@ func NewListBox(name string, config ListBox) *ListBox {
 	w.IDFunc(func() string {
 		return fmt.Sprintf("ListBox<%s>", name)
 	})
+	w.SetFocusable(true)
 
 	w.SetStyle(Theme.ListBox)
 
@@ -92,10 +110,9 @@ func (w *ListBox) Supervise(s *Supervisor) {
 	w.supervisor = s
 	w.scrollbar.Supervise(s)
 
-	// Add all the list items to be supervised.
-	for _, c := range w.children {
-		w.supervisor.Add(c.Frame)
-	}
+	// The list items get their mouse events by propagation through the
+	// ListBox, see the handlers in setup().
+	w.supervisor.Add(w)
 }
 
 // AddLabel adds a simple text-based label to the Listbox.
@@ -125,58 +142,6 @@ func (w *ListBox) AddLabel(label string, value interface{}, f func()) {
 		Value: value,
 	})
 
-	// Event handlers for the item row.
-	// row.Handle(MouseOver, func(ed EventData) error {
-	// 	if ed.Point.Inside(AbsoluteRect(w.scrollbar)) {
-	// 		return nil // ignore if over scrollbar
-	// 	}
-
-	// 	row.SetBackground(w.style.HoverBackground)
-	// 	child.Font.Color = w.style.HoverForeground
-	// 	return nil
-	// })
-	row.Handle(MouseMove, func(ed EventData) error {
-		if ed.Point.Inside(AbsoluteRect(w.scrollbar)) {
-			// we wandered onto the scrollbar, cancel mouseover
-			return row.Event(MouseOut, ed)
-		}
-		row.SetBackground(w.style.HoverBackground)
-		child.Font.Color = w.style.HoverForeground
-		return nil
-	})
-	row.Handle(MouseOut, func(ed EventData) error {
-		if cur, ok := w.GetValue(); ok && cur == value {
-			row.SetBackground(w.style.SelectedBackground)
-			child.Font.Color = w.style.SelectedForeground
-		} else {
-			fmt.Printf("couldn't get value? %+v %+v\n", cur, ok)
-			row.SetBackground(w.style.Background)
-			child.Font.Color = w.style.Foreground
-		}
-		return nil
-	})
-	row.Handle(MouseUp, func(ed EventData) error {
-		if cur, ok := w.GetValue(); ok && cur == value {
-			row.SetBackground(w.style.SelectedBackground)
-			child.Font.Color = w.style.SelectedForeground
-		} else {
-			row.SetBackground(w.style.Background)
-			child.Font.Color = w.style.Foreground
-		}
-		return nil
-	})
-
-	row.Handle(Click, func(ed EventData) error {
-		// Trigger if we are not hovering over the (overlapping) scrollbar.
-		if !ed.Point.Inside(AbsoluteRect(w.scrollbar)) {
-			w.Event(Change, EventData{
-				Supervisor: w.supervisor,
-				Value:      value,
-			})
-		}
-		return nil
-	})
-
 	// Append the item into the ListBox frame.
 	w.Frame.Pack(row, Pack{
 		Side: N,
@@ -185,15 +150,78 @@ func (w *ListBox) AddLabel(label string, value interface{}, f func()) {
 	})
 
 	// If the current text label isn't in the options, pick
-	// the first option.
-	if _, ok := w.GetValue(); !ok {
-		w.Variable = w.children[0].Value
+	// the first option. A Binding keeps its value.
+	if w.Binding != nil {
+		if valuesEqual(value, w.Variable) {
+			row.SetBackground(w.style.SelectedBackground)
+		}
+	} else if _, ok := w.GetValue(); !ok {
+		w.setVariable(w.children[0].Value)
 		row.SetBackground(w.style.SelectedBackground)
 	}
 }
 
 // TODO: RemoveItem()
 
+// rowAt returns the list item that holds the target widget of a mouse event,
+// or nil if the cursor is not over a list item (e.g. it's on the scrollbar).
+func (w *ListBox) rowAt(ed EventData) *ListValue {
+	// The scrollbar overlaps the rows.
+	if ed.Point.Inside(AbsoluteRect(w.scrollbar)) {
+		return nil
+	}
+
+	for node := ed.Target; node != nil; {
+		for _, row := range w.children {
+			if node == row.Frame {
+				return row
+			}
+		}
+
+		parent, ok := node.Parent()
+		if !ok {
+			break
+		}
+		node = parent
+	}
+	return nil
+}
+
+// styleRow sets the colors of a list item for its selected or hover state.
+func (w *ListBox) styleRow(row *ListValue, hover bool) {
+	var (
+		bg = w.style.Background
+		fg = w.style.Foreground
+	)
+	if hover {
+		bg = w.style.HoverBackground
+		fg = w.style.HoverForeground
+	} else if cur, ok := w.GetValue(); ok && cur == row {
+		bg = w.style.SelectedBackground
+		fg = w.style.SelectedForeground
+	}
+
+	row.Frame.SetBackground(bg)
+	if label, ok := row.Label.(*Label); ok {
+		label.Font.Color = fg
+	}
+}
+
+// setHoverRow moves the hover highlight to a different list item (or none).
+func (w *ListBox) setHoverRow(row *ListValue) {
+	if row == w.hoverRow {
+		return
+	}
+
+	if w.hoverRow != nil {
+		w.styleRow(w.hoverRow, false)
+	}
+	w.hoverRow = row
+	if row != nil {
+		w.styleRow(row, true)
+	}
+}
+
 // GetValue returns the currently selected item in the ListBox.
 //
 // Returns the SelectValue and true on success, and the Label or underlying Value
@@ -212,7 +240,7 @@ func (w *ListBox) GetValue() (*ListValue, bool) {
 func (w *ListBox) SetValueByLabel(label string) bool {
 	for _, option := range w.children {
 		if child, ok := option.Label.(*Label); ok && child.Text == label {
-			w.Variable = option.Value
+			w.setVariable(option.Value)
 			return true
 		}
 	}
@@ -221,19 +249,52 @@ func (w *ListBox) SetValueByLabel(label string) bool {
 
 // SetValue sets the currently selected option to the given value.
 func (w *ListBox) SetValue(value interface{}) bool {
-	w.Variable = value
+	w.setVariable(value)
 	for _, option := range w.children {
 		if option.Value == value {
-			w.Variable = option.Value
 			return true
 		}
 	}
 	return false
 }
 
+// setVariable sets the selected value and its Binding.
+func (w *ListBox) setVariable(value interface{}) {
+	w.Variable = value
+	if w.Binding != nil {
+		w.Binding.Set(value)
+	}
+	w.Invalidate()
+}
+
+// Dirty returns whether the list box changed since it was last drawn,
+// including a change of its Binding.
+func (w *ListBox) Dirty() bool {
+	return w.Frame.Dirty() || (w.Binding != nil && !valuesEqual(w.Binding.Get(), w.Variable))
+}
+
+// Destroy unsubscribes the list box from its Binding.
+func (w *ListBox) Destroy() {
+	w.bound.release()
+	w.Frame.Destroy()
+}
+
+// styleRows sets the colors of all the list items.
+func (w *ListBox) styleRows() {
+	for _, row := range w.children {
+		w.styleRow(row, row == w.hoverRow)
+	}
+}
+
 // Compute to re-evaluate the button state (in the case of radio buttons where
 // a different button will affect the state of this one when clicked).
 func (w *ListBox) Compute(e render.Engine) {
+	// Follow the Binding when it was changed elsewhere.
+	if w.Binding != nil && w.bound.changed(w.Binding) {
+		w.Variable = w.Binding.Get()
+		w.styleRows()
+	}
+
 	w.computeVisible()
 	w.Frame.Compute(e)
 }
@@ -245,7 +306,7 @@ func (w *ListBox) setup() {
 	// 	BorderStyle: BorderSunken,
 	// 	Background:  theme.InputBackgroundColor,
 	// })
-	w.scrollbar = NewScrollBar(ScrollBar{})
+	w.scrollbar = NewScrollBar(ScrollBar{Step: w.Step})
 	w.scrollbar.Handle(Scroll, func(ed EventData) error {
 		fmt.Printf("Scroll event: %f%% unit %d\n", ed.ScrollFraction*100, ed.ScrollUnits)
 		w.scrollFraction = ed.ScrollFraction
@@ -256,6 +317,47 @@ func (w *ListBox) setup() {
 		FillY:   true,
 		Padding: 0,
 	})
+
+	// Mouse events on the list items bubble up to the ListBox.
+	w.HandleBubble(MouseMove, func(ed EventData) error {
+		w.setHoverRow(w.rowAt(ed))
+		return nil
+	})
+	w.HandleBubble(MouseOut, func(ed EventData) error {
+		// The cursor left the row (or the whole ListBox).
+		if w.hoverRow != nil && !ed.Point.Inside(AbsoluteRect(w.hoverRow.Frame)) {
+			w.setHoverRow(nil)
+		}
+		return nil
+	})
+	w.HandleBubble(MouseUp, func(ed EventData) error {
+		if row := w.rowAt(ed); row != nil {
+			w.styleRow(row, false)
+		}
+		return nil
+	})
+	w.HandleBubble(Click, func(ed EventData) error {
+		if row := w.rowAt(ed); row != nil {
+			if w.Binding != nil {
+				w.setVariable(row.Value)
+				w.styleRows()
+			}
+			w.Event(Change, EventData{
+				Supervisor: w.supervisor,
+				Value:      row.Value,
+			})
+		}
+		return nil
+	})
+
+	// Scrolling the mouse wheel over the list moves its scrollbar.
+	w.HandleBubble(Scroll, func(ed EventData) error {
+		if ed.WheelY != 0 {
+			w.scrollbar.ScrollBy(-ed.WheelY)
+			return ErrStopPropagation
+		}
+		return nil
+	})
 	// w.Frame.Pack(w.list, Pack{
 	// 	Side:   E,
 	// 	FillY:  true,
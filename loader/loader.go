@@ -0,0 +1,336 @@
+// Package loader builds go/ui widget trees from JSON or YAML documents.
+//
+// A document describes a tree of widgets with their Config properties, Pack,
+// Place, Grid or Flex options and fonts. Named styles may be shared between widgets,
+// Labels and Checkboxes may be bound to the fields of a Go struct, and event
+// handlers are looked up by name from the Loader. For example:
+//
+//	styles:
+//	  title:
+//	    font: {size: 16, color: "#000099"}
+//	root:
+//	  type: Frame
+//	  name: Settings
+//	  children:
+//	    - type: Label
+//	      text: Settings
+//	      style: title
+//	      pack: {side: N}
+//	    - type: Checkbox
+//	      text: Enable sound
+//	      bind: Sound
+//	      pack: {side: N, fillX: true}
+//	    - type: Button
+//	      name: Save
+//	      text: Save
+//	      on: {click: save}
+//	      pack: {side: N, padY: 4}
+//
+// The Go side binds the data and the handlers:
+//
+//	l := loader.New(supervisor)
+//	l.Bind(&settings)
+//	l.Handle("save", func(ed ui.EventData) error { ... })
+//	root, err := l.LoadFile("settings.yml")
+package loader
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"git.kirsle.net/go/ui"
+	"gopkg.in/yaml.v3"
+)
+
+// Document is a UI definition.
+type Document struct {
+	Styles map[string]Style `json:"styles" yaml:"styles"`
+	Root   Node             `json:"root" yaml:"root"`
+}
+
+// Node describes a widget and its children.
+type Node struct {
+	Type  string `json:"type" yaml:"type"`   // widget type, e.g. "Frame", "Button"
+	Name  string `json:"name" yaml:"name"`   // widget name, used in its ID
+	Text  string `json:"text" yaml:"text"`   // text of a Label, Button, Checkbox or Window title
+	Style string `json:"style" yaml:"style"` // names of styles to apply, separated by spaces
+
+	Config *Config `json:"config" yaml:"config"`
+	Font   *Font   `json:"font" yaml:"font"`
+	Pack   *Pack   `json:"pack" yaml:"pack"`
+	Place  *Place  `json:"place" yaml:"place"`
+	Grid   *Grid   `json:"grid" yaml:"grid"`
+	Flex   *Flex   `json:"flex" yaml:"flex"`
+
+	// FlexLayout of a Frame, for its children with Flex options.
+	FlexLayout *FlexLayout `json:"flexLayout" yaml:"flexLayout"`
+
+	// Variable binding: the name of a field of the bound struct, e.g.
+	// "Sound" or "Audio.Volume". A radio Checkbox also has a Value.
+	Bind  string `json:"bind" yaml:"bind"`
+	Value string `json:"value" yaml:"value"`
+
+	// Event handlers by event name, e.g. {"click": "save"}.
+	On map[string]string `json:"on" yaml:"on"`
+
+	Children []Node `json:"children" yaml:"children"`
+}
+
+// Constructor creates a widget of a type for a node. The Loader applies
+// the node's Config, styles and event handlers afterwards, and builds its
+// children if the widget is a container with Pack and Place methods. Other
+// widgets may build their children themselves with Loader.Node.
+type Constructor func(l *Loader, n *Node) (ui.Widget, error)
+
+// Handler is a named event handler.
+type Handler func(ui.EventData) error
+
+// Loader builds widget trees from documents.
+type Loader struct {
+	Supervisor *ui.Supervisor // required for interactive widgets
+
+	data     interface{}
+	handlers map[string]Handler
+	types    map[string]Constructor
+	styles   map[string]Style // of the document being built
+}
+
+// New creates a Loader for a Supervisor, with the built-in widget types.
+func New(s *ui.Supervisor) *Loader {
+	l := &Loader{
+		Supervisor: s,
+		handlers:   map[string]Handler{},
+		types:      map[string]Constructor{},
+	}
+	for name, fn := range builtinTypes {
+		l.types[name] = fn
+	}
+	return l
+}
+
+// Bind a pointer to a struct whose fields the nodes may bind to.
+func (l *Loader) Bind(data interface{}) {
+	l.data = data
+}
+
+// Handle registers a named event handler.
+func (l *Loader) Handle(name string, fn Handler) {
+	l.handlers[name] = fn
+}
+
+// RegisterType registers a constructor for a custom widget type, or
+// replaces a built-in one.
+func (l *Loader) RegisterType(name string, fn Constructor) {
+	l.types[name] = fn
+}
+
+// Parse a document. The format is "json" or "yaml".
+func Parse(data []byte, format string) (*Document, error) {
+	var doc Document
+	switch strings.ToLower(format) {
+	case "json":
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return nil, err
+		}
+	case "yaml", "yml":
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("loader: unknown format %q", format)
+	}
+	return &doc, nil
+}
+
+// Load parses a document and builds its widget tree.
+func (l *Loader) Load(data []byte, format string) (ui.Widget, error) {
+	doc, err := Parse(data, format)
+	if err != nil {
+		return nil, err
+	}
+	return l.Build(doc)
+}
+
+// LoadFile loads a .json, .yaml or .yml file and builds its widget tree.
+func (l *Loader) LoadFile(filename string) (ui.Widget, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	w, err := l.Load(data, strings.TrimPrefix(filepath.Ext(filename), "."))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return w, nil
+}
+
+// Build the widget tree of a document.
+func (l *Loader) Build(doc *Document) (ui.Widget, error) {
+	l.styles = doc.Styles
+	defer func() {
+		l.styles = nil
+	}()
+	return l.Node(&doc.Root)
+}
+
+// Node builds the widget tree of a node. Custom constructors may call it
+// for the nodes they handle themselves.
+func (l *Loader) Node(n *Node) (ui.Widget, error) {
+	fn, ok := l.types[n.Type]
+	if !ok {
+		return nil, fmt.Errorf("loader: unknown widget type %q", n.Type)
+	}
+
+	w, err := fn(l, n)
+	if err != nil {
+		return nil, fmt.Errorf("loader: %s %q: %w", n.Type, n.Name, err)
+	}
+
+	// Config from the styles, then the node itself.
+	var config Config
+	for _, name := range strings.Fields(n.Style) {
+		style, ok := l.styles[name]
+		if !ok {
+			return nil, fmt.Errorf("loader: %s %q: unknown style %q", n.Type, n.Name, name)
+		}
+		config.merge(style.Config)
+	}
+	if n.Config != nil {
+		config.merge(*n.Config)
+	}
+	if configurable, ok := w.(interface{ Configure(ui.Config) }); ok {
+		uiConfig, err := config.toConfig()
+		if err != nil {
+			return nil, fmt.Errorf("loader: %s %q: %w", n.Type, n.Name, err)
+		}
+		configurable.Configure(uiConfig)
+	}
+
+	// Event handlers.
+	for name, handler := range n.On {
+		event, ok := events[name]
+		if !ok {
+			return nil, fmt.Errorf("loader: %s %q: unknown event %q", n.Type, n.Name, name)
+		}
+		fn, ok := l.handlers[handler]
+		if !ok {
+			return nil, fmt.Errorf("loader: %s %q: unknown handler %q", n.Type, n.Name, handler)
+		}
+		w.Handle(event, fn)
+		if l.Supervisor != nil {
+			l.Supervisor.Add(w)
+		}
+	}
+
+	// Children of containers.
+	if layout := n.FlexLayout; layout != nil {
+		flexer, ok := w.(flexer)
+		if !ok {
+			return nil, fmt.Errorf("loader: %s %q: can't have a flex layout", n.Type, n.Name)
+		}
+		flexLayout, err := layout.toFlexLayout()
+		if err != nil {
+			return nil, err
+		}
+		flexer.SetFlexLayout(flexLayout)
+	}
+	if container, ok := w.(container); ok {
+		for i := range n.Children {
+			child, err := l.Node(&n.Children[i])
+			if err != nil {
+				return nil, err
+			}
+
+			if place := n.Children[i].Place; place != nil {
+				container.Place(child, place.toPlace())
+			} else if grid := n.Children[i].Grid; grid != nil {
+				gridder, ok := w.(gridder)
+				if !ok {
+					return nil, fmt.Errorf("loader: %s %q: can't grid its children", n.Type, n.Name)
+				}
+				gridConfig, err := grid.toGrid()
+				if err != nil {
+					return nil, err
+				}
+				gridder.Grid(child, gridConfig)
+			} else if flex := n.Children[i].Flex; flex != nil {
+				flexer, ok := w.(flexer)
+				if !ok {
+					return nil, fmt.Errorf("loader: %s %q: can't flex its children", n.Type, n.Name)
+				}
+				flexer.Flex(child, flex.toFlex())
+			} else if pack := n.Children[i].Pack; pack != nil {
+				packConfig, err := pack.toPack()
+				if err != nil {
+					return nil, err
+				}
+				container.Pack(child, packConfig)
+			} else {
+				container.Pack(child)
+			}
+		}
+	}
+
+	return w, nil
+}
+
+// container is a widget that packs and places children, like a Frame.
+type container interface {
+	Pack(ui.Widget, ...ui.Pack)
+	Place(ui.Widget, ui.Place)
+}
+
+// gridder is a container that lays out children in a grid.
+type gridder interface {
+	Grid(ui.Widget, ui.Grid)
+}
+
+// flexer is a container that lays out children with a flex layout.
+type flexer interface {
+	Flex(ui.Widget, ui.Flex)
+	SetFlexLayout(ui.FlexLayout)
+}
+
+// font returns the font of a node, from its styles and its own Font. The
+// zero value means the widget's default.
+func (l *Loader) font(n *Node) Font {
+	var font Font
+	for _, name := range strings.Fields(n.Style) {
+		if style, ok := l.styles[name]; ok && style.Font != nil {
+			font.merge(*style.Font)
+		}
+	}
+	if n.Font != nil {
+		font.merge(*n.Font)
+	}
+	return font
+}
+
+// events maps the event names used in documents to ui Events.
+var events = map[string]ui.Event{
+	"mouseOver":   ui.MouseOver,
+	"mouseMove":   ui.MouseMove,
+	"mouseOut":    ui.MouseOut,
+	"mouseDown":   ui.MouseDown,
+	"mouseUp":     ui.MouseUp,
+	"click":       ui.Click,
+	"rightClick":  ui.RightClick,
+	"middleClick": ui.MiddleClick,
+	"doubleClick": ui.DoubleClick,
+	"keyDown":     ui.KeyDown,
+	"keyUp":       ui.KeyUp,
+	"keyPress":    ui.KeyPress,
+	"scroll":      ui.Scroll,
+	"change":      ui.Change,
+	"focusIn":     ui.FocusIn,
+	"focusOut":    ui.FocusOut,
+	"drop":        ui.Drop,
+	"dragEnter":   ui.DragEnter,
+	"dragOver":    ui.DragOver,
+	"dragLeave":   ui.DragLeave,
+	"closeWindow": ui.CloseWindow,
+}
@@ -0,0 +1,321 @@
+package loader
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"git.kirsle.net/go/render/event"
+	"git.kirsle.net/go/ui"
+	"git.kirsle.net/go/ui/softrender"
+)
+
+type settings struct {
+	Sound bool
+	Audio struct {
+		Device string
+	}
+	Name  string
+	Score *ui.IntValue
+}
+
+const settingsYAML = `
+styles:
+  title:
+    font: {size: 16, padX: 4}
+  boxed:
+    borderSize: 2
+    borderStyle: sunken
+root:
+  type: Frame
+  name: Settings
+  config: {width: 200}
+  children:
+    - type: Label
+      text: Settings
+      style: title
+      pack: {side: N}
+    - type: Checkbox
+      name: Sound
+      text: Enable sound
+      bind: Sound
+      pack: {side: N, fillX: true}
+    - type: Frame
+      name: Devices
+      style: boxed
+      pack: {side: N, padY: 4}
+      children:
+        - type: Checkbox
+          name: Speakers
+          text: Speakers
+          bind: Audio.Device
+          value: speakers
+          pack: {side: W}
+        - type: Checkbox
+          name: Headphones
+          text: Headphones
+          bind: Audio.Device
+          value: headphones
+          pack: {side: W}
+    - type: Label
+      bind: Name
+      place: {x: 10, y: 100}
+    - type: Swatch
+      name: Color
+    - type: Button
+      name: Save
+      text: Save
+      on: {click: save}
+      pack: {side: S}
+`
+
+const settingsJSON = `{
+  "styles": {
+    "title": {"font": {"size": 16, "padX": 4}},
+    "boxed": {"borderSize": 2, "borderStyle": "sunken"}
+  },
+  "root": {
+    "type": "Frame", "name": "Settings", "config": {"width": 200},
+    "children": [
+      {"type": "Label", "text": "Settings", "style": "title", "pack": {"side": "N"}},
+      {"type": "Checkbox", "name": "Sound", "text": "Enable sound", "bind": "Sound", "pack": {"side": "N", "fillX": true}},
+      {"type": "Frame", "name": "Devices", "style": "boxed", "pack": {"side": "N", "padY": 4}, "children": [
+        {"type": "Checkbox", "name": "Speakers", "text": "Speakers", "bind": "Audio.Device", "value": "speakers", "pack": {"side": "W"}},
+        {"type": "Checkbox", "name": "Headphones", "text": "Headphones", "bind": "Audio.Device", "value": "headphones", "pack": {"side": "W"}}
+      ]},
+      {"type": "Label", "bind": "Name", "place": {"x": 10, "y": 100}},
+      {"type": "Swatch", "name": "Color"},
+      {"type": "Button", "name": "Save", "text": "Save", "on": {"click": "save"}, "pack": {"side": "S"}}
+    ]
+  }
+}`
+
+// newLoader returns a Loader with the handlers and custom types used by
+// the test documents.
+func newLoader(data *settings, saved *int) *Loader {
+	l := New(ui.NewSupervisor())
+	l.Bind(data)
+	l.Handle("save", func(ed ui.EventData) error {
+		*saved++
+		return nil
+	})
+	l.RegisterType("Swatch", func(l *Loader, n *Node) (ui.Widget, error) {
+		w := ui.NewFrame(n.Name)
+		w.IDFunc(func() string {
+			return "Swatch<" + n.Name + ">"
+		})
+		return w, nil
+	})
+	return l
+}
+
+func TestLoad(t *testing.T) {
+	var (
+		data  settings
+		saved int
+		trees []string
+	)
+	data.Name = "Player 1"
+
+	for _, doc := range []struct {
+		format string
+		text   string
+	}{
+		{"yaml", settingsYAML},
+		{"json", settingsJSON},
+	} {
+		l := newLoader(&data, &saved)
+		root, err := l.Load([]byte(doc.text), doc.format)
+		if err != nil {
+			t.Fatalf("%s: %s", doc.format, err)
+		}
+		trees = append(trees, strings.Join(ui.WidgetTree(root), "\n"))
+
+		if root.Size().W != 200 || !root.FixedSize() {
+			t.Errorf("%s: expected the configured width, got %s", doc.format, root.Size())
+		}
+
+		devices := ui.FindByID(root, "Frame<Devices>")
+		if devices == nil || devices.BorderSize() != 2 || devices.BorderStyle() != ui.BorderSunken {
+			t.Errorf("%s: expected the boxed style on the Devices frame", doc.format)
+		}
+
+		if w, _ := ui.Query(root, `Label#"Player 1"`); w == nil {
+			t.Errorf("%s: expected a label bound to the name", doc.format)
+		}
+		if ui.FindByID(root, "Swatch<Color>") == nil {
+			t.Errorf("%s: expected the custom widget type", doc.format)
+		}
+
+		// Bound variables and handlers.
+		save, _ := ui.Query(root, "Button#Save")
+		if save == nil {
+			t.Fatalf("%s: no save button", doc.format)
+		}
+		save.Event(ui.Click, ui.EventData{})
+		check, _ := ui.Query(root, "CheckButton")
+		if check == nil {
+			t.Fatalf("%s: no sound checkbox", doc.format)
+		}
+		check.Event(ui.Click, ui.EventData{})
+		if data.Sound != (doc.format == "yaml") {
+			t.Errorf("%s: expected the checkbox to toggle the bound variable", doc.format)
+		}
+	}
+
+	if trees[0] != trees[1] {
+		t.Errorf("expected the same tree from YAML and JSON, got:\n%s\n\n%s", trees[0], trees[1])
+	}
+	if saved != 2 {
+		t.Errorf("expected the save handler to run twice, got %d", saved)
+	}
+}
+
+func TestLoadGrid(t *testing.T) {
+	const doc = `
+root:
+  type: Frame
+  children:
+    - {type: Label, name: A, text: Name, grid: {row: 0, sticky: w}}
+    - {type: Label, name: B, text: Player 1, grid: {row: 0, column: 1}}
+    - {type: Label, name: C, text: Difficulty, grid: {row: 1, sticky: w}}
+`
+	root, err := New(nil).Load([]byte(doc), "yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	root.Compute(softrender.New(200, 100))
+
+	var (
+		value, _ = ui.Query(root, `Label#"Player 1"`)
+		long, _  = ui.Query(root, `Label#Difficulty`)
+	)
+	if value == nil || long == nil || value.Point().X != long.Size().W {
+		t.Errorf("expected the second column after the widest label of the first")
+	}
+}
+
+func TestLoadFlex(t *testing.T) {
+	const doc = `
+root:
+  type: Frame
+  config: {width: 100}
+  flexLayout: {wrap: true, gap: 4, justify: spaceBetween}
+  children:
+    - {type: Frame, name: A, config: {width: 40, height: 10}, flex: {}}
+    - {type: Frame, name: B, config: {width: 40, height: 10}, flex: {}}
+    - {type: Frame, name: C, config: {width: 40, height: 10}, flex: {grow: 1}}
+`
+	root, err := New(nil).Load([]byte(doc), "yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	root.Compute(softrender.New(200, 100))
+
+	var (
+		b, _ = ui.Query(root, `Frame#B`)
+		c, _ = ui.Query(root, `Frame#C`)
+	)
+	if b == nil || c == nil || c.Point().Y != 14 || c.Size().W != b.Point().X+b.Size().W {
+		t.Errorf("expected the third frame to wrap and grow to the width of the first line")
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	var data settings
+	for _, test := range []struct {
+		doc    string
+		expect string
+	}{
+		{"root: {type: Bogus}", `unknown widget type "Bogus"`},
+		{"root: {type: Frame, style: nope}", `unknown style "nope"`},
+		{"root: {type: Button, on: {click: nope}}", `unknown handler "nope"`},
+		{"root: {type: Button, on: {explode: save}}", `unknown event "explode"`},
+		{"root: {type: Label, bind: Nope}", "no field Nope"},
+		{"root: {type: Label, bind: Sound}", "can't bind a Label"},
+		{"root: {type: Label, bind: Score}", "the field is nil"},
+		{"root: {type: Frame, config: {borderStyle: wavy}}", `unknown border style "wavy"`},
+		{"root: {type: Frame, children: [{type: Frame, pack: {side: up}}]}", `unknown pack side "up"`},
+		{"root: {type: Frame, children: [{type: Frame, grid: {sticky: up}}]}", `unknown grid sticky "up"`},
+		{"root: {type: Frame, flexLayout: {direction: up}}", `unknown flex direction "up"`},
+		{"root: {type: Label, flexLayout: {}}", "can't have a flex layout"},
+	} {
+		l := newLoader(&data, new(int))
+		_, err := l.Load([]byte(test.doc), "yaml")
+		if err == nil || !strings.Contains(err.Error(), test.expect) {
+			t.Errorf("%s: expected error %q, got %v", test.doc, test.expect, err)
+		}
+	}
+}
+
+func TestWatch(t *testing.T) {
+	var (
+		dir      = t.TempDir()
+		filename = filepath.Join(dir, "ui.yml")
+		now      = time.Unix(0, 0)
+		s        = ui.NewSupervisor()
+		l        = New(s)
+		loaded   []ui.Widget
+		errs     []error
+	)
+	s.SetClock(func() time.Time { return now })
+
+	write := func(text string, mtime time.Time) {
+		if err := ioutil.WriteFile(filename, []byte(text), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(filename, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("root: {type: Button, name: One, text: One}", now)
+
+	timer, err := l.Watch(filename, time.Second, func(w ui.Widget, err error) {
+		loaded = append(loaded, w)
+		errs = append(errs, err)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded) != 1 || loaded[0] == nil || loaded[0].ID() != "Button<One>" {
+		t.Fatalf("expected the file to be loaded at once, got %v %v", loaded, errs)
+	}
+
+	// tick advances the clock and runs the timers.
+	tick := func() {
+		now = now.Add(time.Second)
+		s.Loop(event.NewState())
+	}
+
+	tick()
+	if len(loaded) != 1 {
+		t.Errorf("expected no reload of an unchanged file, got %d loads", len(loaded))
+	}
+
+	write("root: {type: Button, name: Two, text: Two}", now.Add(time.Hour))
+	tick()
+	if len(loaded) != 2 || loaded[1] == nil || loaded[1].ID() != "Button<Two>" {
+		t.Fatalf("expected the changed file to be reloaded, got %v %v", loaded, errs)
+	}
+	for slot := range s.Widgets() {
+		if slot.Widget() == loaded[0] {
+			t.Error("expected the old tree to be removed from the Supervisor")
+		}
+	}
+
+	write("root: {type: Bogus}", now.Add(2*time.Hour))
+	tick()
+	if len(errs) != 3 || errs[2] == nil {
+		t.Errorf("expected an error for a broken file, got %v", errs)
+	}
+
+	timer.Stop()
+	write("root: {type: Frame}", now.Add(3*time.Hour))
+	tick()
+	if len(loaded) != 3 {
+		t.Errorf("expected no reloads after Stop, got %d loads", len(loaded))
+	}
+}
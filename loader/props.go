@@ -0,0 +1,350 @@
+package loader
+
+import (
+	"fmt"
+	"strings"
+
+	"git.kirsle.net/go/render"
+	"git.kirsle.net/go/ui"
+)
+
+// Style is a named set of properties that nodes can share.
+type Style struct {
+	Config `yaml:",inline"`
+	Font   *Font `json:"font" yaml:"font"`
+}
+
+// Config holds the ui.Config properties of a node. Colors are hex codes
+// like "#ff9900".
+type Config struct {
+	Width        int    `json:"width" yaml:"width"`
+	Height       int    `json:"height" yaml:"height"`
+	Margin       int    `json:"margin" yaml:"margin"`
+	MarginX      int    `json:"marginX" yaml:"marginX"`
+	MarginY      int    `json:"marginY" yaml:"marginY"`
+	Background   string `json:"background" yaml:"background"`
+	Foreground   string `json:"foreground" yaml:"foreground"`
+	BorderSize   int    `json:"borderSize" yaml:"borderSize"`
+	BorderStyle  string `json:"borderStyle" yaml:"borderStyle"` // solid, raised or sunken
+	BorderColor  string `json:"borderColor" yaml:"borderColor"`
+	OutlineSize  int    `json:"outlineSize" yaml:"outlineSize"`
+	OutlineColor string `json:"outlineColor" yaml:"outlineColor"`
+}
+
+// merge the properties that are set in another Config.
+func (c *Config) merge(o Config) {
+	mergeInt(&c.Width, o.Width)
+	mergeInt(&c.Height, o.Height)
+	mergeInt(&c.Margin, o.Margin)
+	mergeInt(&c.MarginX, o.MarginX)
+	mergeInt(&c.MarginY, o.MarginY)
+	mergeString(&c.Background, o.Background)
+	mergeString(&c.Foreground, o.Foreground)
+	mergeInt(&c.BorderSize, o.BorderSize)
+	mergeString(&c.BorderStyle, o.BorderStyle)
+	mergeString(&c.BorderColor, o.BorderColor)
+	mergeInt(&c.OutlineSize, o.OutlineSize)
+	mergeString(&c.OutlineColor, o.OutlineColor)
+}
+
+// toConfig converts to a ui.Config.
+func (c Config) toConfig() (ui.Config, error) {
+	var (
+		result = ui.Config{
+			Width:       c.Width,
+			Height:      c.Height,
+			Margin:      c.Margin,
+			MarginX:     c.MarginX,
+			MarginY:     c.MarginY,
+			BorderSize:  c.BorderSize,
+			OutlineSize: c.OutlineSize,
+		}
+		err error
+	)
+
+	switch ui.BorderStyle(c.BorderStyle) {
+	case ui.BorderNone, ui.BorderSolid, ui.BorderRaised, ui.BorderSunken:
+		result.BorderStyle = ui.BorderStyle(c.BorderStyle)
+	default:
+		return result, fmt.Errorf("unknown border style %q", c.BorderStyle)
+	}
+
+	for _, color := range []struct {
+		hex string
+		dst *render.Color
+	}{
+		{c.Background, &result.Background},
+		{c.Foreground, &result.Foreground},
+		{c.BorderColor, &result.BorderColor},
+		{c.OutlineColor, &result.OutlineColor},
+	} {
+		if *color.dst, err = parseColor(color.hex); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// Font holds the render.Text properties of a node.
+type Font struct {
+	Size         int    `json:"size" yaml:"size"`
+	Color        string `json:"color" yaml:"color"`
+	Stroke       string `json:"stroke" yaml:"stroke"`
+	Shadow       string `json:"shadow" yaml:"shadow"`
+	Padding      int    `json:"padding" yaml:"padding"`
+	PadX         int    `json:"padX" yaml:"padX"`
+	PadY         int    `json:"padY" yaml:"padY"`
+	FontFilename string `json:"fontFilename" yaml:"fontFilename"`
+}
+
+// merge the properties that are set in another Font.
+func (f *Font) merge(o Font) {
+	mergeInt(&f.Size, o.Size)
+	mergeString(&f.Color, o.Color)
+	mergeString(&f.Stroke, o.Stroke)
+	mergeString(&f.Shadow, o.Shadow)
+	mergeInt(&f.Padding, o.Padding)
+	mergeInt(&f.PadX, o.PadX)
+	mergeInt(&f.PadY, o.PadY)
+	mergeString(&f.FontFilename, o.FontFilename)
+}
+
+// toText converts to a render.Text, starting from a widget's default font.
+func (f Font) toText(base render.Text) (render.Text, error) {
+	var err error
+	if f == (Font{}) {
+		return base, nil
+	}
+
+	mergeInt(&base.Size, f.Size)
+	mergeInt(&base.Padding, f.Padding)
+	mergeInt(&base.PadX, f.PadX)
+	mergeInt(&base.PadY, f.PadY)
+	mergeString(&base.FontFilename, f.FontFilename)
+	for _, color := range []struct {
+		hex string
+		dst *render.Color
+	}{
+		{f.Color, &base.Color},
+		{f.Stroke, &base.Stroke},
+		{f.Shadow, &base.Shadow},
+	} {
+		if color.hex == "" {
+			continue
+		}
+		if *color.dst, err = parseColor(color.hex); err != nil {
+			return base, err
+		}
+	}
+	return base, nil
+}
+
+// Pack holds the ui.Pack options of a node.
+type Pack struct {
+	Side    string `json:"side" yaml:"side"` // N, NE, E, SE, S, SW, W, NW or Center
+	Fill    bool   `json:"fill" yaml:"fill"`
+	FillX   bool   `json:"fillX" yaml:"fillX"`
+	FillY   bool   `json:"fillY" yaml:"fillY"`
+	Padding int    `json:"padding" yaml:"padding"`
+	PadX    int    `json:"padX" yaml:"padX"`
+	PadY    int    `json:"padY" yaml:"padY"`
+	Expand  bool   `json:"expand" yaml:"expand"`
+}
+
+// sides maps the side names to ui Sides.
+var sides = map[string]ui.Side{
+	"":       ui.Center,
+	"center": ui.Center,
+	"n":      ui.N,
+	"ne":     ui.NE,
+	"e":      ui.E,
+	"se":     ui.SE,
+	"s":      ui.S,
+	"sw":     ui.SW,
+	"w":      ui.W,
+	"nw":     ui.NW,
+}
+
+// toPack converts to a ui.Pack.
+func (p Pack) toPack() (ui.Pack, error) {
+	side, ok := sides[strings.ToLower(p.Side)]
+	if !ok {
+		return ui.Pack{}, fmt.Errorf("loader: unknown pack side %q", p.Side)
+	}
+	return ui.Pack{
+		Side:    side,
+		Fill:    p.Fill,
+		FillX:   p.FillX,
+		FillY:   p.FillY,
+		Padding: p.Padding,
+		PadX:    p.PadX,
+		PadY:    p.PadY,
+		Expand:  p.Expand,
+	}, nil
+}
+
+// Place holds the ui.Place options of a node: either a point (X, Y) or
+// distances to the edges of the parent.
+type Place struct {
+	X      int  `json:"x" yaml:"x"`
+	Y      int  `json:"y" yaml:"y"`
+	Top    int  `json:"top" yaml:"top"`
+	Left   int  `json:"left" yaml:"left"`
+	Right  int  `json:"right" yaml:"right"`
+	Bottom int  `json:"bottom" yaml:"bottom"`
+	Center bool `json:"center" yaml:"center"`
+	Middle bool `json:"middle" yaml:"middle"`
+}
+
+// toPlace converts to a ui.Place.
+func (p Place) toPlace() ui.Place {
+	return ui.Place{
+		Point:  render.NewPoint(p.X, p.Y),
+		Top:    p.Top,
+		Left:   p.Left,
+		Right:  p.Right,
+		Bottom: p.Bottom,
+		Center: p.Center,
+		Middle: p.Middle,
+	}
+}
+
+// Grid holds the ui.Grid options of a node. Sticky is made of the letters
+// of the sides to stick to, e.g. "w" or "nsew".
+type Grid struct {
+	Row        int    `json:"row" yaml:"row"`
+	Column     int    `json:"column" yaml:"column"`
+	RowSpan    int    `json:"rowSpan" yaml:"rowSpan"`
+	ColumnSpan int    `json:"columnSpan" yaml:"columnSpan"`
+	Sticky     string `json:"sticky" yaml:"sticky"`
+	PadX       int    `json:"padX" yaml:"padX"`
+	PadY       int    `json:"padY" yaml:"padY"`
+}
+
+// toGrid converts to a ui.Grid.
+func (g Grid) toGrid() (ui.Grid, error) {
+	var sticky ui.Sticky
+	for _, c := range strings.ToLower(g.Sticky) {
+		switch c {
+		case 'n':
+			sticky |= ui.StickyN
+		case 'e':
+			sticky |= ui.StickyE
+		case 's':
+			sticky |= ui.StickyS
+		case 'w':
+			sticky |= ui.StickyW
+		default:
+			return ui.Grid{}, fmt.Errorf("loader: unknown grid sticky %q", g.Sticky)
+		}
+	}
+	return ui.Grid{
+		Row:        g.Row,
+		Column:     g.Column,
+		RowSpan:    g.RowSpan,
+		ColumnSpan: g.ColumnSpan,
+		Sticky:     sticky,
+		PadX:       g.PadX,
+		PadY:       g.PadY,
+	}, nil
+}
+
+// Flex holds the ui.Flex options of a node.
+type Flex struct {
+	Grow   int `json:"grow" yaml:"grow"`
+	Shrink int `json:"shrink" yaml:"shrink"`
+	Basis  int `json:"basis" yaml:"basis"`
+}
+
+// toFlex converts to a ui.Flex.
+func (f Flex) toFlex() ui.Flex {
+	return ui.Flex{
+		Grow:   f.Grow,
+		Shrink: f.Shrink,
+		Basis:  f.Basis,
+	}
+}
+
+// FlexLayout holds the ui.FlexLayout options of a Frame node. Direction is
+// "row" or "column", Justify is "start", "end", "center", "spaceBetween",
+// "spaceAround" or "spaceEvenly" and Align is "start", "end", "center" or
+// "stretch".
+type FlexLayout struct {
+	Direction string `json:"direction" yaml:"direction"`
+	Wrap      bool   `json:"wrap" yaml:"wrap"`
+	Justify   string `json:"justify" yaml:"justify"`
+	Align     string `json:"align" yaml:"align"`
+	Gap       int    `json:"gap" yaml:"gap"`
+}
+
+// The names of the flex layout options, lowercased.
+var (
+	flexDirections = map[string]ui.FlexDirection{
+		"":       ui.FlexRow,
+		"row":    ui.FlexRow,
+		"column": ui.FlexColumn,
+	}
+	flexJustifies = map[string]ui.FlexJustify{
+		"":             ui.JustifyStart,
+		"start":        ui.JustifyStart,
+		"end":          ui.JustifyEnd,
+		"center":       ui.JustifyCenter,
+		"spacebetween": ui.JustifySpaceBetween,
+		"spacearound":  ui.JustifySpaceAround,
+		"spaceevenly":  ui.JustifySpaceEvenly,
+	}
+	flexAligns = map[string]ui.FlexAlign{
+		"":        ui.AlignStart,
+		"start":   ui.AlignStart,
+		"end":     ui.AlignEnd,
+		"center":  ui.AlignCenter,
+		"stretch": ui.AlignStretch,
+	}
+)
+
+// toFlexLayout converts to a ui.FlexLayout.
+func (f FlexLayout) toFlexLayout() (ui.FlexLayout, error) {
+	direction, ok := flexDirections[strings.ToLower(f.Direction)]
+	if !ok {
+		return ui.FlexLayout{}, fmt.Errorf("loader: unknown flex direction %q", f.Direction)
+	}
+	justify, ok := flexJustifies[strings.ToLower(f.Justify)]
+	if !ok {
+		return ui.FlexLayout{}, fmt.Errorf("loader: unknown flex justify %q", f.Justify)
+	}
+	align, ok := flexAligns[strings.ToLower(f.Align)]
+	if !ok {
+		return ui.FlexLayout{}, fmt.Errorf("loader: unknown flex align %q", f.Align)
+	}
+	return ui.FlexLayout{
+		Direction: direction,
+		Wrap:      f.Wrap,
+		Justify:   justify,
+		Align:     align,
+		Gap:       f.Gap,
+	}, nil
+}
+
+// parseColor parses a hex color, or returns Invisible for "".
+func parseColor(hex string) (render.Color, error) {
+	if hex == "" {
+		return render.Invisible, nil
+	}
+	color, err := render.HexColor(hex)
+	if err != nil {
+		return color, fmt.Errorf("bad color %q: %w", hex, err)
+	}
+	return color, nil
+}
+
+func mergeInt(dst *int, v int) {
+	if v != 0 {
+		*dst = v
+	}
+}
+
+func mergeString(dst *string, v string) {
+	if v != "" {
+		*dst = v
+	}
+}
@@ -0,0 +1,58 @@
+package loader
+
+import (
+	"errors"
+	"os"
+	"time"
+
+	"git.kirsle.net/go/ui"
+)
+
+// DefaultWatchInterval is how often Watch checks the file for changes.
+var DefaultWatchInterval = 500 * time.Millisecond
+
+// Watch loads a file and calls fn with its widget tree, then again each
+// time the file changes, so a UI can be edited while the program runs.
+//
+// The file is checked with a Supervisor timer, so fn is called from
+// Supervisor.Loop and may swap the new tree into the UI. The widgets of the
+// previous tree are removed from the Supervisor after fn returns. If the
+// file fails to load, fn gets the error and the previous tree stays.
+//
+// Stop the returned Timer to stop watching.
+func (l *Loader) Watch(filename string, interval time.Duration, fn func(ui.Widget, error)) (*ui.Timer, error) {
+	if l.Supervisor == nil {
+		return nil, errors.New("loader: Watch requires a Supervisor")
+	}
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+
+	var (
+		stat, _ = os.Stat(filename)
+		current ui.Widget
+		load    = func() {
+			w, err := l.LoadFile(filename)
+			if err != nil {
+				fn(nil, err)
+				return
+			}
+
+			fn(w, nil)
+			if current != nil {
+				l.Supervisor.RemoveTree(current)
+			}
+			current = w
+		}
+	)
+	load()
+
+	return l.Supervisor.Every(interval, func() {
+		latest, err := os.Stat(filename)
+		if err != nil || (stat != nil && latest.ModTime().Equal(stat.ModTime()) && latest.Size() == stat.Size()) {
+			return
+		}
+		stat = latest
+		load()
+	}), nil
+}
@@ -0,0 +1,211 @@
+package loader
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"git.kirsle.net/go/ui"
+)
+
+// builtinTypes are the widget types every Loader knows.
+var builtinTypes = map[string]Constructor{
+	"Frame":       newFrame,
+	"ScrollFrame": newScrollFrame,
+	"Label":       newLabel,
+	"Button":      newButton,
+	"CheckButton": newCheckButton,
+	"Checkbox":    newCheckbox,
+	"Window":      newWindow,
+}
+
+// newFrame makes a Frame.
+func newFrame(l *Loader, n *Node) (ui.Widget, error) {
+	return ui.NewFrame(n.Name), nil
+}
+
+// newScrollFrame makes a ScrollFrame, whose children are packed or placed
+// into its scrolled content.
+func newScrollFrame(l *Loader, n *Node) (ui.Widget, error) {
+	w := ui.NewScrollFrame(n.Name)
+	if l.Supervisor != nil {
+		w.Supervise(l.Supervisor)
+	}
+	return w, nil
+}
+
+// newLabel makes a Label. It may be bound to a string or int field.
+func newLabel(l *Loader, n *Node) (ui.Widget, error) {
+	font, err := l.font(n).toText(ui.DefaultFont)
+	if err != nil {
+		return nil, err
+	}
+
+	config := ui.Label{
+		Text: n.Text,
+		Font: font,
+	}
+	if n.Bind != "" {
+		field, err := l.field(n.Bind)
+		if err != nil {
+			return nil, err
+		}
+		switch v := field.(type) {
+		case *string:
+			config.TextVariable = v
+		case *int:
+			config.IntVariable = v
+		default:
+			// A field holding an observable value, e.g. *ui.IntValue.
+			if binding, ok := reflect.ValueOf(field).Elem().Interface().(ui.Binding); ok {
+				config.Binding = binding
+				break
+			}
+			return nil, fmt.Errorf("can't bind a Label to %s (%T)", n.Bind, field)
+		}
+	}
+	return ui.NewLabel(config), nil
+}
+
+// newButton makes a Button with a Label of its text, or else its first
+// child as its content.
+func newButton(l *Loader, n *Node) (ui.Widget, error) {
+	child, err := l.content(n)
+	if err != nil {
+		return nil, err
+	}
+
+	w := ui.NewButton(n.Name, child)
+	if l.Supervisor != nil {
+		l.Supervisor.Add(w)
+	}
+	return w, nil
+}
+
+// newCheckButton makes a CheckButton bound to a bool field, or a radio
+// button bound to a string field if the node has a Value.
+func newCheckButton(l *Loader, n *Node) (ui.Widget, error) {
+	child, err := l.content(n)
+	if err != nil {
+		return nil, err
+	}
+
+	field, err := l.field(n.Bind)
+	if err != nil {
+		return nil, err
+	}
+
+	var w *ui.CheckButton
+	switch v := field.(type) {
+	case *bool:
+		w = ui.NewCheckButton(n.Name, v, child)
+	case *string:
+		w = ui.NewRadioButton(n.Name, v, n.Value, child)
+	case **ui.BoolValue:
+		w = ui.NewCheckButtonValue(n.Name, *v, child)
+	case **ui.StringValue:
+		w = ui.NewRadioButtonValue(n.Name, *v, n.Value, child)
+	default:
+		return nil, fmt.Errorf("can't bind a CheckButton to %s (%T)", n.Bind, field)
+	}
+	if l.Supervisor != nil {
+		l.Supervisor.Add(w)
+	}
+	return w, nil
+}
+
+// newCheckbox makes a Checkbox bound to a bool field, or a Radiobox bound
+// to a string field if the node has a Value.
+func newCheckbox(l *Loader, n *Node) (ui.Widget, error) {
+	child, err := l.content(n)
+	if err != nil {
+		return nil, err
+	}
+
+	field, err := l.field(n.Bind)
+	if err != nil {
+		return nil, err
+	}
+
+	var w *ui.Checkbox
+	switch v := field.(type) {
+	case *bool:
+		w = ui.NewCheckbox(n.Name, v, child)
+	case *string:
+		w = ui.NewRadiobox(n.Name, v, n.Value, child)
+	case **ui.BoolValue:
+		w = ui.NewCheckboxValue(n.Name, *v, child)
+	case **ui.StringValue:
+		w = ui.NewRadioboxValue(n.Name, *v, n.Value, child)
+	default:
+		return nil, fmt.Errorf("can't bind a Checkbox to %s (%T)", n.Bind, field)
+	}
+	if l.Supervisor != nil {
+		w.Supervise(l.Supervisor)
+	}
+	return w, nil
+}
+
+// newWindow makes a Window titled by its text, managed by the Supervisor.
+func newWindow(l *Loader, n *Node) (ui.Widget, error) {
+	title := n.Text
+	if title == "" {
+		title = n.Name
+	}
+
+	w := ui.NewWindow(title)
+	if l.Supervisor != nil {
+		w.Supervise(l.Supervisor)
+	}
+	return w, nil
+}
+
+// content returns the content of a button-like widget: a Label of the
+// node's text, or else its only child node.
+func (l *Loader) content(n *Node) (ui.Widget, error) {
+	if n.Text != "" || len(n.Children) == 0 {
+		return newLabel(l, &Node{
+			Text:  n.Text,
+			Style: n.Style,
+			Font:  n.Font,
+		})
+	}
+	if len(n.Children) > 1 {
+		return nil, fmt.Errorf("expected one child, got %d", len(n.Children))
+	}
+	return l.Node(&n.Children[0])
+}
+
+// field returns a pointer to a field of the bound struct, e.g. *bool or
+// **ui.BoolValue, by its name or dotted path.
+func (l *Loader) field(name string) (interface{}, error) {
+	if name == "" {
+		return nil, fmt.Errorf("a bind field is required")
+	}
+	if l.data == nil {
+		return nil, fmt.Errorf("can't bind %s: no data is bound to the Loader", name)
+	}
+
+	v := reflect.ValueOf(l.data)
+	for _, part := range strings.Split(name, ".") {
+		for v.Kind() == reflect.Ptr {
+			v = v.Elem()
+		}
+		if v.Kind() != reflect.Struct {
+			return nil, fmt.Errorf("can't bind %s: %s is not a struct", name, v.Type())
+		}
+
+		v = v.FieldByName(part)
+		if !v.IsValid() {
+			return nil, fmt.Errorf("can't bind %s: no field %s", name, part)
+		}
+	}
+
+	if !v.CanAddr() || !v.Addr().CanInterface() {
+		return nil, fmt.Errorf("can't bind %s: the field is not exported", name)
+	}
+	if v.Kind() == reflect.Ptr && v.IsNil() {
+		return nil, fmt.Errorf("can't bind %s: the field is nil", name)
+	}
+	return v.Addr().Interface(), nil
+}
@@ -23,13 +23,24 @@ var (
 )
 
 // MainWindow is the parent window of a UI application.
+//
+// By default the window computes and draws all of its widgets on every frame.
+// Set Idle for it to only draw when something changed: a widget was
+// invalidated (see BaseWidget.Invalidate), e.g. by user input, a timer or a
+// bound value. Then only the area of the widgets that changed is drawn again,
+// the widgets that are unchanged are not computed again, and between frames
+// the window sleeps until a task is invoked or the Supervisor changes. The
+// render.Engine can only poll for input, so that is still done at the FPS
+// rate.
 type MainWindow struct {
 	Engine        render.Engine
+	Idle          bool // only draw what changed, see above
 	supervisor    *Supervisor
 	frame         *Frame
 	loopCallbacks []func(*event.State)
 	w             int
 	h             int
+	damage        render.Rect // area drawn on the last frame in Idle mode
 }
 
 // NewMainWindow initializes the MainWindow. You should probably only have one
@@ -164,7 +175,9 @@ func (mw *MainWindow) MainLoop() error {
 
 // Loop does one loop of the UI.
 func (mw *MainWindow) Loop() error {
-	mw.Engine.Clear(render.White)
+	if !mw.Idle {
+		mw.Engine.Clear(render.White)
+	}
 
 	// Record how long this loop took.
 	start := time.Now()
@@ -182,6 +195,9 @@ func (mw *MainWindow) Loop() error {
 			mw.h = h
 			mw.resized()
 		}
+
+		// Draw the window again after it was resized, restored or exposed.
+		mw.frame.Invalidate()
 	}
 
 	// Ping any loop callbacks.
@@ -189,13 +205,17 @@ func (mw *MainWindow) Loop() error {
 		cb(ev)
 	}
 
-	mw.frame.Compute(mw.Engine)
+	if mw.Idle {
+		mw.supervisor.Loop(ev)
+		mw.redraw()
+	} else {
+		mw.frame.Compute(mw.Engine)
 
-	// Render the child widgets.
-	mw.supervisor.Loop(ev)
-	mw.frame.Present(mw.Engine, mw.frame.Point())
-	mw.supervisor.Present(mw.Engine)
-	mw.Engine.Present()
+		// Render the child widgets.
+		mw.supervisor.Loop(ev)
+		mw.supervisor.Compute(mw.Engine)
+		mw.present(mw.Engine)
+	}
 
 	// Delay to maintain target frames per second.
 	var delay uint32
@@ -204,7 +224,48 @@ func (mw *MainWindow) Loop() error {
 	if targetFPS-int(elapsed) > 0 {
 		delay = uint32(targetFPS - int(elapsed))
 	}
-	mw.Engine.Delay(delay)
+	if mw.Idle {
+		mw.supervisor.wait(time.Duration(delay) * time.Millisecond)
+	} else {
+		mw.Engine.Delay(delay)
+	}
 
 	return nil
 }
+
+// redraw draws the widgets that changed since the last frame, in Idle mode.
+func (mw *MainWindow) redraw() {
+	var s = mw.supervisor
+	if !s.NeedsRedraw(mw.frame) {
+		return
+	}
+	mw.frame.Compute(mw.Engine)
+	s.Compute(mw.Engine)
+
+	if damage, ok := s.Damage(mw.frame); ok {
+		// The engine may be double buffered and draw on top of the frame
+		// before the last one: draw the last frame's changes again too.
+		var (
+			area = unionRect(damage, mw.damage)
+			clip = Clip(mw.Engine)
+		)
+		clip.PushClip(area)
+		clip.DrawBox(render.White, area)
+		mw.present(clip)
+		clip.PopClip()
+		mw.damage = damage
+	} else {
+		mw.Engine.Clear(render.White)
+		mw.present(mw.Engine)
+		mw.damage = render.NewRect(mw.w, mw.h)
+	}
+
+	s.Drawn(mw.frame)
+}
+
+// present draws the widgets to the window.
+func (mw *MainWindow) present(e render.Engine) {
+	mw.frame.Present(e, mw.frame.Point())
+	mw.supervisor.Present(e)
+	mw.Engine.Present()
+}
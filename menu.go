@@ -18,6 +18,14 @@ type Menu struct {
 	supervisor *Supervisor
 	body       *Frame
 	items      []*MenuItem
+
+	// Widget that opens the menu (e.g. its MenuButton), used to scope the
+	// keyboard shortcuts of the menu items.
+	owner Widget
+
+	// Popup menus position themselves at this point when presented.
+	popup      bool
+	popupPoint render.Point
 }
 
 // NewMenu creates a new Menu. It is hidden by default. Usually you'll
@@ -55,7 +63,18 @@ func (w *Menu) Supervise(s *Supervisor) {
 	w.supervisor = s
 	for _, item := range w.items {
 		w.supervisor.Add(item)
+		w.addShortcut(item)
+	}
+}
+
+// addShortcut registers the keyboard shortcut of a menu item, if it has an
+// Accelerator, with the supervisor.
+func (w *Menu) addShortcut(item *MenuItem) {
+	if item.Accelerator == "" || item.Command == nil || item.shortcut != nil {
+		return
 	}
+
+	item.shortcut, item.shortcutErr = w.supervisor.addShortcut(item.Accelerator, nil, w.owner, item.Command)
 }
 
 // Compute the menu
@@ -76,6 +95,11 @@ func (w *Menu) Compute(e render.Engine) {
 	}
 	w.body.Resize(render.NewRect(maxWidth, height))
 
+	// A popup menu has no MenuButton to position it.
+	if w.popup {
+		w.positionNear(e, w.popupPoint, render.Rect{}, 0)
+	}
+
 	// Call the BaseWidget Compute in case we have subscribers.
 	w.BaseWidget.Compute(e)
 }
@@ -134,9 +158,108 @@ func (w *Menu) Pack(item *MenuItem) {
 	})
 	if w.supervisor != nil {
 		w.supervisor.Add(item)
+		w.addShortcut(item)
 	}
 }
 
+// positionNear moves the menu next to an anchor box on screen, such as the
+// MenuButton that opens it or the mouse cursor for a context menu. Usually,
+// the menu appears below and to the right of the anchor. But if the menu will
+// hit a window boundary, its position will be adjusted to fit the window while
+// trying not to overlap the anchor. The gap is the spacing between the anchor
+// and the menu.
+func (w *Menu) positionNear(e render.Engine, anchorPoint render.Point, anchorSize render.Rect, gap int) {
+	var (
+		// Size of the actual desktop window.
+		Width, Height = e.WindowSize()
+	)
+
+	// Ideal location: below and to the right of the anchor.
+	w.MoveTo(render.Point{
+		X: anchorPoint.X,
+		Y: anchorPoint.Y + anchorSize.H + gap,
+	})
+
+	var (
+		// Size of the menu.
+		menuPoint = w.Point()
+		menuSize  = w.Rect()
+		margin    = 8  // keep away from directly touching window edges
+		topMargin = 32 // keep room for standard Menu Bar
+	)
+
+	// Will we clip out the bottom of the window?
+	if menuPoint.Y+menuSize.H+margin > Height {
+		// Put us above the anchor instead, with the bottom of the
+		// menu touching the top of the anchor.
+		menuPoint = render.Point{
+			X: anchorPoint.X,
+			Y: anchorPoint.Y - menuSize.H - gap,
+		}
+
+		// If this would put us over the TOP edge of the window now,
+		// cap the movement so the top of the menu is visible. We can't
+		// avoid overlapping the anchor with the menu so might as well
+		// start now.
+		if menuPoint.Y < topMargin {
+			menuPoint.Y = topMargin
+		}
+
+		w.MoveTo(menuPoint)
+	}
+
+	// Will we clip out the right of the window?
+	if menuPoint.X+menuSize.W > Width {
+		// Move us in from the right side of the window.
+		var delta = Width - menuSize.W - margin
+		w.MoveTo(render.Point{
+			X: delta,
+			Y: menuPoint.Y,
+		})
+	}
+}
+
+// Popup shows the menu as a modal at a point on screen, e.g. the mouse cursor
+// for a context menu. The menu keeps clear of the window edges the same way
+// as a MenuButton's menu does, and closes when an item is picked or the user
+// clicks outside of it. The menu is sized and positioned by Supervisor.Compute.
+func (w *Menu) Popup(s *Supervisor, p render.Point) {
+	if w.supervisor != s {
+		w.Supervise(s)
+	}
+
+	// Handle closing the menu when clicked outside.
+	if !w.hasHandler(CloseModal) {
+		w.Handle(CloseModal, func(ed EventData) error {
+			ed.Supervisor.PopModal(w)
+			return nil
+		})
+	}
+
+	w.popup = true
+	w.popupPoint = p
+	w.MoveTo(p)
+	w.Show()
+	if s.GetModal() != w {
+		s.PushModal(w)
+	}
+}
+
+// ContextMenu attaches a Menu to a widget, to pop up at the mouse cursor when
+// the widget is right-clicked. The widget must also be supervised for it to
+// receive the RightClick event.
+func (s *Supervisor) ContextMenu(w Widget, menu *Menu) {
+	if menu.owner == nil {
+		menu.owner = w
+	}
+	menu.Supervise(s)
+
+	w.Handle(RightClick, func(ed EventData) error {
+		menu.Popup(s, ed.Point)
+		return nil
+	})
+}
+
 // Size returns the size of the menu's body.
 func (w *Menu) Size() render.Rect {
 	return w.body.Size()
@@ -163,12 +286,21 @@ type MenuItem struct {
 	Command     func()
 	separator   bool
 	button      *Button
+	shortcut    *Shortcut // registered keyboard shortcut for the Accelerator
+	shortcutErr error     // why the Accelerator couldn't be registered
 
 	// store of most recent bg color set on a menu item
 	cacheBg render.Color
 	cacheFg render.Color
 }
 
+// ShortcutError returns why the item's Accelerator couldn't be registered as
+// a keyboard shortcut once its Menu was supervised, e.g. because it doesn't
+// parse or another shortcut already has it, or nil if it was registered.
+func (w *MenuItem) ShortcutError() error {
+	return w.shortcutErr
+}
+
 // NewMenuItem creates a new menu item.
 func NewMenuItem(label, accelerator string, command func()) *MenuItem {
 	w := &MenuItem{
@@ -179,6 +311,7 @@ func NewMenuItem(label, accelerator string, command func()) *MenuItem {
 	w.IDFunc(func() string {
 		return fmt.Sprintf("MenuItem<%s>", w.Label)
 	})
+	w.SetFocusable(true)
 
 	font := DefaultFont
 	font.Color = render.Black
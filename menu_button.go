@@ -41,6 +41,7 @@ func NewMenuButton(name string, child Widget) *MenuButton {
 	w.IDFunc(func() string {
 		return fmt.Sprintf("MenuButton<%s>", name)
 	})
+	w.SetFocusable(true)
 
 	w.setup()
 	return w
@@ -54,6 +55,15 @@ func (w *MenuButton) Supervise(s *Supervisor) {
 	w.menu.Supervise(s)
 }
 
+// ownedWidgets returns the popup menu, which is not a child of the button
+// but goes with it when the button is removed or destroyed.
+func (w *MenuButton) ownedWidgets() []Widget {
+	if w.menu == nil {
+		return nil
+	}
+	return []Widget{w.menu}
+}
+
 // AddItem adds a new option to the MenuButton's menu.
 func (w *MenuButton) AddItem(label string, f func()) {
 	w.initMenu()
@@ -86,59 +96,7 @@ func (w *MenuButton) Compute(e render.Engine) {
 // the button. But if the menu will hit a window boundary, its position will
 // be adjusted to fit the window while trying not to overlap its own button.
 func (w *MenuButton) positionMenu(e render.Engine) {
-	var (
-		// Position and size of the MenuButton button.
-		buttonPoint = AbsolutePosition(w)
-		buttonSize  = w.Size()
-
-		// Size of the actual desktop window.
-		Width, Height = e.WindowSize()
-	)
-
-	// Ideal location: below and to the right of the button.
-	w.menu.MoveTo(render.Point{
-		X: buttonPoint.X,
-		Y: buttonPoint.Y + buttonSize.H + w.BoxThickness(2),
-	})
-
-	var (
-		// Size of the menu.
-		menuPoint = w.menu.Point()
-		menuSize  = w.menu.Rect()
-		margin    = 8  // keep away from directly touching window edges
-		topMargin = 32 // keep room for standard Menu Bar
-	)
-
-	// Will we clip out the bottom of the window?
-	if menuPoint.Y+menuSize.H+margin > Height {
-		// Put us above the button instead, with the bottom of the
-		// menu touching the top of the button.
-		menuPoint = render.Point{
-			X: buttonPoint.X,
-			Y: buttonPoint.Y - menuSize.H - w.BoxThickness(2),
-		}
-
-		// If this would put us over the TOP edge of the window now,
-		// cap the movement so the top of the menu is visible. We can't
-		// avoid overlapping the button with the menu so might as well
-		// start now.
-		if menuPoint.Y < topMargin {
-			menuPoint.Y = topMargin
-		}
-
-		w.menu.MoveTo(menuPoint)
-	}
-
-	// Will we clip out the right of the window?
-	if menuPoint.X+menuSize.W > Width {
-		// Move us in from the right side of the window.
-		var delta = Width - menuSize.W - margin
-		w.menu.MoveTo(render.Point{
-			X: delta,
-			Y: menuPoint.Y,
-		})
-	}
-	_ = Width
+	w.menu.positionNear(e, AbsolutePosition(w), w.Size(), w.BoxThickness(2))
 }
 
 // setup the common things between checkboxes and radioboxes.
@@ -184,6 +142,7 @@ func (w *MenuButton) setup() {
 func (w *MenuButton) initMenu() {
 	if w.menu == nil {
 		w.menu = NewMenu(w.name + ":Menu")
+		w.menu.owner = w
 		w.menu.Hide()
 
 		// Handle closing the menu when clicked outside.
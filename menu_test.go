@@ -1,7 +1,12 @@
 package ui_test
 
 import (
+	"testing"
+
+	"git.kirsle.net/go/render"
+	"git.kirsle.net/go/render/event"
 	"git.kirsle.net/go/ui"
+	"git.kirsle.net/go/ui/softrender"
 )
 
 // Example of using the menu widgets.
@@ -15,8 +20,8 @@ func ExampleMenu() {
 	menu := ui.NewMenuBar("Main Menu")
 
 	// File menu. Some items with accelerators, some without.
-	// NOTE: key bindings are up to you, the accelerators are
-	// purely decorative.
+	// The accelerators are registered as keyboard shortcuts with
+	// the Supervisor when the MenuBar is supervised.
 	file := menu.AddMenu("File")
 	file.AddItemAccel("New", "Ctrl-N", func() {})
 	file.AddItemAccel("Open", "Ctrl-O", func() {})
@@ -42,6 +47,7 @@ func ExampleMenu() {
 
 	// Each loop you must then:
 	// - Call Supervisor.Loop() as normal to handle events.
+	// - Call Supervisor.Compute() to size and position the popup menus.
 	// - Call Supervisor.Present() to draw the modal popup menus.
 	// MainLoop() of the MainWindow does this for you.
 	mw.MainLoop()
@@ -77,7 +83,51 @@ func ExampleMenuButton() {
 
 	// Each loop you must then:
 	// - Call Supervisor.Loop() as normal to handle events.
+	// - Call Supervisor.Compute() to size and position the popup menus.
 	// - Call Supervisor.Present() to draw the modal popup menus.
 	// MainLoop() of the MainWindow does this for you.
 	mw.MainLoop()
 }
+
+// Right-clicking a widget pops up its context menu at the cursor, kept clear
+// of the window edges.
+func TestContextMenu(t *testing.T) {
+	var (
+		engine = softrender.New(300, 200)
+		s      = ui.NewSupervisor()
+		canvas = ui.NewFrame("Canvas")
+		menu   = ui.NewMenu("Context")
+	)
+	canvas.Resize(render.NewRect(300, 200))
+	s.Add(canvas)
+	menu.AddItem("Cut", func() {})
+	menu.AddItem("Copy", func() {})
+	s.ContextMenu(canvas, menu)
+
+	ev := event.NewState()
+	ev.CursorX, ev.CursorY = 290, 190
+	ev.Button2 = true
+	s.Loop(ev)
+	if s.GetModal() != nil {
+		t.Errorf("expected no menu until the button is released")
+	}
+	ev.Button2 = false
+	s.Loop(ev)
+
+	if s.GetModal() != ui.Widget(menu) {
+		t.Fatalf("expected the menu to be pushed as a modal, got %v", s.GetModal())
+	}
+
+	// Computing the menu moves it above the cursor and in from the right edge.
+	s.Compute(engine)
+	var (
+		P = menu.Point()
+		R = menu.Rect()
+	)
+	if R.W == 0 || R.H == 0 {
+		t.Fatalf("expected the menu to have a size, got %dx%d", R.W, R.H)
+	}
+	if P.X != 300-R.W-8 || P.Y != 190-R.H {
+		t.Errorf("expected the menu at %d,%d, got %d,%d", 300-R.W-8, 190-R.H, P.X, P.Y)
+	}
+}
@@ -0,0 +1,308 @@
+package ui
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"unicode"
+)
+
+// FindByID searches a widget tree, depth first, for the widget with the
+// given ID (e.g. "Button<Close>"). The root widget is included. Returns nil
+// if there is no such widget.
+func FindByID(root Widget, id string) Widget {
+	var found Widget
+	crawlTree(root, func(w Widget, ancestors []Widget) bool {
+		if w.ID() == id {
+			found = w
+			return false
+		}
+		return true
+	})
+	return found
+}
+
+// Selector is a compiled query for widgets, see ParseSelector.
+type Selector struct {
+	text   string
+	groups [][]selectorStep
+}
+
+// selectorStep is one compound selector, e.g. `Button#Save*:focused`, and
+// how it relates to the step before it.
+type selectorStep struct {
+	child  bool   // '>' combinator: the previous step matches the parent
+	typ    string // widget type, e.g. "Button", or "" for any
+	name   string // glob pattern for the name in the widget ID
+	pseudo []string
+}
+
+// Pseudo-classes for the state of a widget.
+var selectorPseudo = map[string]func(Widget) bool{
+	"hidden":    func(w Widget) bool { return w.Hidden() },
+	"visible":   func(w Widget) bool { return !w.Hidden() },
+	"focused":   func(w Widget) bool { return hasFocus(w) },
+	"focusable": func(w Widget) bool { return isFocusable(w) },
+}
+
+// ParseSelector compiles a CSS-like selector for widgets.
+//
+// A widget ID has the form `Type<name>`, e.g. `Button<Close>` or
+// `Label<"Enable sound">`. The selector syntax is:
+//
+//	Button             widgets of a type ("*" for any type)
+//	#Close             widgets by name, with glob patterns: #Save*, #"Enable *"
+//	:hidden            state: hidden, visible, focused or focusable
+//	Frame Button       a Button anywhere inside a Frame
+//	Frame > Button     a Button that is a direct child of a Frame
+//	Button, Label      either selector
+//
+// A compound selector combines them, e.g. `Window#Settings Button:focused`.
+// A Window's name is its Title.
+func ParseSelector(selector string) (Selector, error) {
+	var (
+		result = Selector{text: selector}
+		steps  []selectorStep
+		child  bool
+		runes  = []rune(selector)
+		i      int
+	)
+
+	for {
+		// Skip whitespace between compound selectors.
+		for i < len(runes) && unicode.IsSpace(runes[i]) {
+			i++
+		}
+		if i == len(runes) {
+			break
+		}
+
+		switch runes[i] {
+		case ',':
+			if len(steps) == 0 || child {
+				return result, fmt.Errorf("ParseSelector(%q): unexpected ','", selector)
+			}
+			result.groups = append(result.groups, steps)
+			steps = nil
+			i++
+			continue
+		case '>':
+			if len(steps) == 0 || child {
+				return result, fmt.Errorf("ParseSelector(%q): unexpected '>'", selector)
+			}
+			child = true
+			i++
+			continue
+		}
+
+		step := selectorStep{child: child}
+		child = false
+
+		// Type name.
+		start := i
+		for i < len(runes) && isSelectorIdent(runes[i]) {
+			i++
+		}
+		step.typ = string(runes[start:i])
+		if step.typ == "*" {
+			step.typ = ""
+		}
+
+		// Name and pseudo-classes.
+		for i < len(runes) && (runes[i] == '#' || runes[i] == ':') {
+			switch runes[i] {
+			case '#':
+				i++
+				if i < len(runes) && runes[i] == '"' {
+					end := i + 1
+					for end < len(runes) && runes[end] != '"' {
+						end++
+					}
+					if end == len(runes) {
+						return result, fmt.Errorf("ParseSelector(%q): unterminated quote", selector)
+					}
+					step.name = string(runes[i+1 : end])
+					i = end + 1
+				} else {
+					start := i
+					for i < len(runes) && isSelectorIdent(runes[i]) {
+						i++
+					}
+					step.name = string(runes[start:i])
+				}
+				if _, err := path.Match(step.name, ""); err != nil {
+					return result, fmt.Errorf("ParseSelector(%q): bad pattern %q", selector, step.name)
+				}
+			case ':':
+				i++
+				start := i
+				for i < len(runes) && isSelectorIdent(runes[i]) {
+					i++
+				}
+				pseudo := string(runes[start:i])
+				if _, ok := selectorPseudo[pseudo]; !ok {
+					return result, fmt.Errorf("ParseSelector(%q): unknown pseudo-class %q", selector, pseudo)
+				}
+				step.pseudo = append(step.pseudo, pseudo)
+			}
+		}
+
+		if i == start {
+			return result, fmt.Errorf("ParseSelector(%q): unexpected %q", selector, runes[i])
+		}
+		steps = append(steps, step)
+	}
+
+	if len(steps) == 0 || child {
+		return result, fmt.Errorf("ParseSelector(%q): incomplete selector", selector)
+	}
+	result.groups = append(result.groups, steps)
+	return result, nil
+}
+
+// isSelectorIdent returns whether a rune can be part of a type name or an
+// unquoted name pattern.
+func isSelectorIdent(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("_-*?[]", r)
+}
+
+// String returns the selector as it was written.
+func (s Selector) String() string {
+	return s.text
+}
+
+// Match returns whether a widget matches the selector, given its ancestors
+// from the root of the tree down to its parent.
+func (s Selector) Match(w Widget, ancestors []Widget) bool {
+	for _, steps := range s.groups {
+		if matchSteps(steps, w, ancestors) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchSteps matches the last step against the widget and the steps before
+// it against its ancestors.
+func matchSteps(steps []selectorStep, w Widget, ancestors []Widget) bool {
+	last := steps[len(steps)-1]
+	if !last.match(w) {
+		return false
+	} else if len(steps) == 1 {
+		return true
+	}
+
+	if last.child {
+		n := len(ancestors) - 1
+		return n >= 0 && matchSteps(steps[:len(steps)-1], ancestors[n], ancestors[:n])
+	}
+	for n := len(ancestors) - 1; n >= 0; n-- {
+		if matchSteps(steps[:len(steps)-1], ancestors[n], ancestors[:n]) {
+			return true
+		}
+	}
+	return false
+}
+
+// match tests one widget against a compound selector.
+func (step selectorStep) match(w Widget) bool {
+	typ, name := splitWidgetID(w.ID())
+	if window, ok := w.(*Window); ok {
+		name = window.Title // its ID has the focus state too
+	}
+	if step.typ != "" && step.typ != typ {
+		return false
+	}
+	if step.name != "" {
+		if ok, _ := path.Match(step.name, name); !ok {
+			return false
+		}
+	}
+	for _, pseudo := range step.pseudo {
+		if !selectorPseudo[pseudo](w) {
+			return false
+		}
+	}
+	return true
+}
+
+// splitWidgetID splits an ID like `Label<"Hello">` into its type and name,
+// without the quotes.
+func splitWidgetID(id string) (typ, name string) {
+	i := strings.IndexRune(id, '<')
+	if i < 0 || !strings.HasSuffix(id, ">") {
+		return id, id
+	}
+	typ, name = id[:i], id[i+1:len(id)-1]
+	if len(name) >= 2 && strings.HasPrefix(name, `"`) && strings.HasSuffix(name, `"`) {
+		name = name[1 : len(name)-1]
+	}
+	return typ, name
+}
+
+// Query returns the first widget in the tree, depth first, that matches
+// the selector, or nil if none do. The root widget is included.
+func Query(root Widget, selector string) (Widget, error) {
+	sel, err := ParseSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	var found Widget
+	crawlTree(root, func(w Widget, ancestors []Widget) bool {
+		if sel.Match(w, ancestors) {
+			found = w
+			return false
+		}
+		return true
+	})
+	return found, nil
+}
+
+// QueryAll returns all the widgets in the tree that match the selector, in
+// depth first order. The root widget is included.
+func QueryAll(root Widget, selector string) ([]Widget, error) {
+	sel, err := ParseSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	var found []Widget
+	crawlTree(root, func(w Widget, ancestors []Widget) bool {
+		if sel.Match(w, ancestors) {
+			found = append(found, w)
+		}
+		return true
+	})
+	return found, nil
+}
+
+// crawlTree visits a widget tree depth first by its Children, with the
+// ancestors of each widget, until the callback returns false.
+func crawlTree(root Widget, fn func(w Widget, ancestors []Widget) bool) {
+	var (
+		seen  = map[Widget]interface{}{}
+		stop  bool
+		crawl func(Widget, []Widget)
+	)
+	crawl = func(w Widget, ancestors []Widget) {
+		if w == nil || stop {
+			return
+		}
+		if _, ok := seen[w]; ok {
+			return
+		}
+		seen[w] = nil
+
+		if !fn(w, ancestors) {
+			stop = true
+			return
+		}
+
+		ancestors = append(ancestors, w)
+		for _, child := range w.Children() {
+			crawl(child, ancestors[:len(ancestors):len(ancestors)])
+		}
+	}
+	crawl(root, nil)
+}
@@ -0,0 +1,94 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestQuery(t *testing.T) {
+	var (
+		root    = NewFrame("Root")
+		toolbar = NewFrame("Toolbar")
+		save    = NewButton("SaveFile", NewLabel(Label{Text: "Save"}))
+		saveAs  = NewButton("SaveAs", NewLabel(Label{Text: "Save as..."}))
+		panel   = NewFrame("Panel")
+		sound   = NewLabel(Label{Text: "Enable sound"})
+	)
+	root.Pack(toolbar, Pack{Side: N})
+	toolbar.Pack(save, Pack{Side: W})
+	toolbar.Pack(saveAs, Pack{Side: W})
+	root.Pack(panel, Pack{Side: N})
+	panel.Pack(sound, Pack{Side: N})
+	panel.Hide()
+	saveAs.SetHasFocus(true)
+
+	if w := FindByID(root, "Button<SaveAs>"); w != saveAs {
+		t.Errorf("FindByID: expected %s, got %v", saveAs, w)
+	}
+	if w := FindByID(root, "Button<Missing>"); w != nil {
+		t.Errorf("FindByID: expected nil, got %s", w)
+	}
+
+	var tests = []struct {
+		Selector string
+		Expect   []Widget
+	}{
+		{"Button", []Widget{save, saveAs}},
+		{"#Save*", []Widget{save, save.child, saveAs, saveAs.child}},
+		{"Button#SaveAs", []Widget{saveAs}},
+		{`#"Enable *"`, []Widget{sound}},
+		{"Frame > Button", []Widget{save, saveAs}},
+		{"Frame#Root > Button", nil},
+		{"Frame#Root Button > Label", []Widget{save.child, saveAs.child}},
+		{"*:hidden", []Widget{panel, sound}},
+		{"Label:visible", []Widget{save.child, saveAs.child}},
+		{":focused", []Widget{saveAs}},
+		{"Frame:hidden, Button:focused", []Widget{saveAs, panel}},
+	}
+	for _, test := range tests {
+		got, err := QueryAll(root, test.Selector)
+		if err != nil {
+			t.Errorf("QueryAll(%q): %s", test.Selector, err)
+			continue
+		}
+		if !widgetsEqual(got, test.Expect) {
+			t.Errorf("QueryAll(%q): expected %v, got %v", test.Selector, test.Expect, got)
+		}
+	}
+
+	if w, err := Query(root, "Frame Button"); err != nil || w != save {
+		t.Errorf("Query: expected %s, got %v (%v)", save, w, err)
+	}
+
+	// A Window matches by its title, and its widgets are inside its body.
+	var (
+		window = NewWindow("Settings")
+		ok     = NewButton("OK", NewLabel(Label{Text: "OK"}))
+	)
+	window.Pack(ok, Pack{Side: N})
+	ok.SetHasFocus(true)
+	if w, err := Query(window, "Window#Settings Button:focused"); err != nil || w != ok {
+		t.Errorf("Query: expected %s in the window, got %v (%v)", ok, w, err)
+	}
+	if w, err := Query(window, "Window#Settings > Button"); err != nil || w != nil {
+		t.Errorf("Query: expected no direct child Button of the window, got %v (%v)", w, err)
+	}
+
+	for _, bad := range []string{"", "> Button", "Button >", "Button,", ":bogus", `#"open`, "Button!"} {
+		if _, err := ParseSelector(bad); err == nil || !strings.Contains(err.Error(), "ParseSelector") {
+			t.Errorf("ParseSelector(%q): expected an error, got %v", bad, err)
+		}
+	}
+}
+
+func widgetsEqual(a, b []Widget) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,111 @@
+package replay
+
+import (
+	"io"
+	"strings"
+	"time"
+
+	"git.kirsle.net/go/render"
+	"git.kirsle.net/go/ui"
+	"git.kirsle.net/go/ui/softrender"
+)
+
+// Player replays a Session against a Supervisor.
+type Player struct {
+	Session    *Session
+	Supervisor *ui.Supervisor
+
+	// Root widget, if not nil, is computed before and presented after each
+	// Loop as the application's main loop would, on the Engine.
+	Root   ui.Widget
+	Engine render.Engine
+
+	frame int
+	now   time.Time
+}
+
+// resizer is an Engine whose window can be resized, like softrender's.
+type resizer interface {
+	Resize(width, height int)
+}
+
+// NewPlayer prepares to replay a session. The Supervisor's clock is taken
+// over by the Player and follows the recorded times. The Engine has the
+// recorded window size and is resized as the window was.
+func NewPlayer(session *Session, s *ui.Supervisor, root ui.Widget) *Player {
+	width, height := session.Width, session.Height
+	if width == 0 || height == 0 {
+		width, height = ui.DefaultWidth, ui.DefaultHeight
+	}
+
+	p := &Player{
+		Session:    session,
+		Supervisor: s,
+		Root:       root,
+		Engine:     softrender.New(width, height),
+		now:        session.Start,
+	}
+	s.SetClock(p.Now)
+	return p
+}
+
+// Now returns the recorded time of the current frame.
+func (p *Player) Now() time.Time {
+	return p.now
+}
+
+// Frame returns the number of frames replayed so far.
+func (p *Player) Frame() int {
+	return p.frame
+}
+
+// Done returns whether all the frames have been replayed.
+func (p *Player) Done() bool {
+	return p.frame >= len(p.Session.Frames)
+}
+
+// Step replays the next frame. Returns false if there were none left.
+func (p *Player) Step() bool {
+	if p.Done() {
+		return false
+	}
+
+	f := p.Session.Frames[p.frame]
+	p.frame++
+	p.now = p.Session.Start.Add(f.Time)
+
+	if f.Flags&WindowResized != 0 && f.Width > 0 && f.Height > 0 {
+		if e, ok := p.Engine.(resizer); ok {
+			e.Resize(f.Width, f.Height)
+		}
+	}
+
+	if p.Root != nil {
+		p.Root.Compute(p.Engine)
+	}
+	p.Supervisor.Loop(f.State())
+	p.Supervisor.Compute(p.Engine)
+	if p.Root != nil {
+		p.Root.Present(p.Engine, p.Root.Point())
+	}
+	p.Supervisor.Present(p.Engine)
+	return true
+}
+
+// Run replays the frames until the given number of frames has been
+// replayed, e.g. to stop just before a bug happens. A negative frame number
+// replays the whole session.
+func (p *Player) Run(until int) {
+	for (until < 0 || p.frame < until) && p.Step() {
+	}
+}
+
+// DumpTree writes the WidgetTree of the root widget, e.g. after stopping at
+// a frame with Run.
+func (p *Player) DumpTree(w io.Writer) error {
+	if p.Root == nil {
+		return nil
+	}
+	_, err := io.WriteString(w, strings.Join(ui.WidgetTree(p.Root), "\n")+"\n")
+	return err
+}
@@ -0,0 +1,70 @@
+package replay
+
+import (
+	"git.kirsle.net/go/render"
+	"git.kirsle.net/go/render/event"
+	"git.kirsle.net/go/ui"
+)
+
+// Recorder records the input of each Supervisor.Loop into a Session.
+type Recorder struct {
+	supervisor *ui.Supervisor
+	engine     render.Engine
+	session    *Session
+	paused     bool
+}
+
+// NewRecorder starts recording the input to a Supervisor. It works for a
+// MainWindow (use its Supervisor and Engine) and for any custom loop that
+// calls Supervisor.Loop. The window size of the engine is recorded too.
+func NewRecorder(s *ui.Supervisor, e render.Engine) *Recorder {
+	w, h := e.WindowSize()
+	r := &Recorder{
+		supervisor: s,
+		engine:     e,
+		session: &Session{
+			Version: Version,
+			Width:   w,
+			Height:  h,
+		},
+	}
+	s.OnLoop(r.record)
+	return r
+}
+
+// record is the Supervisor's OnLoop hook.
+func (r *Recorder) record(ev *event.State) {
+	if r.paused {
+		return
+	}
+
+	now := r.supervisor.LoopTime()
+	if len(r.session.Frames) == 0 {
+		r.session.Start = now
+	}
+	f := NewFrame(ev, now.Sub(r.session.Start))
+	if ev.WindowResized {
+		f.Width, f.Height = r.engine.WindowSize()
+	}
+	r.session.Frames = append(r.session.Frames, f)
+}
+
+// Pause stops recording until Resume is called.
+func (r *Recorder) Pause() {
+	r.paused = true
+}
+
+// Resume recording after a Pause.
+func (r *Recorder) Resume() {
+	r.paused = false
+}
+
+// Session returns the recording so far.
+func (r *Recorder) Session() *Session {
+	return r.session
+}
+
+// Save the recording so far to a file.
+func (r *Recorder) Save(filename string) error {
+	return r.session.Save(filename)
+}
@@ -0,0 +1,102 @@
+package replay
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"git.kirsle.net/go/render"
+	"git.kirsle.net/go/ui"
+	"git.kirsle.net/go/ui/uitest"
+)
+
+// app is a small UI whose button starts a timer.
+type app struct {
+	s      *ui.Supervisor
+	root   *ui.Frame
+	clicks int
+	fired  []time.Time
+}
+
+func newApp() *app {
+	a := &app{
+		s:    ui.NewSupervisor(),
+		root: ui.NewFrame("Root"),
+	}
+	a.root.Resize(render.NewRect(uitest.DefaultWidth, uitest.DefaultHeight))
+
+	button := ui.NewButton("Start", ui.NewLabel(ui.Label{Text: "Start"}))
+	button.Handle(ui.Click, func(ed ui.EventData) error {
+		a.clicks++
+		a.s.After(100*time.Millisecond, func() {
+			a.fired = append(a.fired, a.s.Now())
+		})
+		return nil
+	})
+	a.s.Add(button)
+	a.root.Pack(button, ui.Pack{Side: ui.N})
+	return a
+}
+
+func TestReplay(t *testing.T) {
+	// Record a session driven by the test driver.
+	rec := newApp()
+	d := uitest.New(t, rec.s, rec.root)
+	r := NewRecorder(rec.s, d.Engine)
+	d.Tick(1)
+	d.Click("Button<Start>")
+	d.Wait(200 * time.Millisecond)
+	if rec.clicks != 1 || len(rec.fired) != 1 {
+		t.Fatalf("recording: expected 1 click and 1 timer, got %d and %d", rec.clicks, len(rec.fired))
+	}
+
+	// The window is resized at the end of the session.
+	d.Engine.Resize(640, 480)
+	d.State().WindowResized = true
+	d.Tick(1)
+	d.State().WindowResized = false
+
+	var buf bytes.Buffer
+	if err := r.Session().Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	session, err := Read(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(session.Frames) != len(r.Session().Frames) {
+		t.Fatalf("expected %d frames after loading, got %d", len(r.Session().Frames), len(session.Frames))
+	}
+
+	// Replay it into a fresh copy of the UI: stop after the first frame.
+	play := newApp()
+	p := NewPlayer(session, play.s, play.root)
+	if w, h := p.Engine.WindowSize(); w != uitest.DefaultWidth || h != uitest.DefaultHeight {
+		t.Errorf("expected the player's window at %dx%d, got %dx%d", uitest.DefaultWidth, uitest.DefaultHeight, w, h)
+	}
+	p.Run(1)
+	if play.clicks != 0 {
+		t.Errorf("expected no clicks at frame 1, got %d", play.clicks)
+	}
+	var tree strings.Builder
+	if err := p.DumpTree(&tree); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(tree.String(), "Start") {
+		t.Errorf("expected the button in the widget tree, got:\n%s", tree.String())
+	}
+
+	// The rest of the session reproduces the click and the timer fires at
+	// the recorded time.
+	p.Run(-1)
+	if !p.Done() || play.clicks != 1 || len(play.fired) != 1 {
+		t.Fatalf("replay: expected 1 click and 1 timer, got %d and %d", play.clicks, len(play.fired))
+	}
+	if got, want := play.fired[0].Sub(session.Start), rec.fired[0].Sub(session.Start); got != want {
+		t.Errorf("expected the timer at %s, got %s", want, got)
+	}
+	if w, h := p.Engine.WindowSize(); w != 640 || h != 480 {
+		t.Errorf("expected the player's window resized to 640x480, got %dx%d", w, h)
+	}
+}
@@ -0,0 +1,169 @@
+// Package replay records the input of a user interface session and replays
+// it deterministically, e.g. to reproduce a bug reported by a player.
+//
+// A Recorder attaches to a Supervisor and saves the event state of each
+// Loop with its time. A Player feeds the frames back to a Supervisor running
+// the same UI, with the Supervisor's clock set to the recorded times so that
+// timers, tweens and double clicks happen just as they did.
+package replay
+
+import (
+	"compress/gzip"
+	"encoding/gob"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"git.kirsle.net/go/render/event"
+)
+
+// Version of the session file format.
+const Version = 1
+
+// Session is a recording of the input to a Supervisor.
+type Session struct {
+	Version int
+	Start   time.Time // clock time of the first frame
+	Width   int       // window size when recording started
+	Height  int
+	Frames  []Frame
+}
+
+// Frame is the input to one Supervisor.Loop.
+type Frame struct {
+	Time    time.Duration // since the start of the session
+	CursorX int
+	CursorY int
+	WheelX  int
+	WheelY  int
+	Flags   Flags    // mouse buttons, modifiers and special keys
+	Keys    []string // other keys held down, by name
+
+	// Window size after a WindowResized frame, else zero.
+	Width  int
+	Height int
+}
+
+// Flags packs the boolean fields of an event state.
+type Flags uint16
+
+// Flag bits for the fields of event.State.
+const (
+	Button1 Flags = 1 << iota
+	Button2
+	Button3
+	Shift
+	Ctrl
+	Alt
+	Escape
+	Enter
+	Up
+	Down
+	Left
+	Right
+	WindowResized
+)
+
+// flagFields maps the flags to the fields of an event state.
+func flagFields(ev *event.State) map[Flags]*bool {
+	return map[Flags]*bool{
+		Button1:       &ev.Button1,
+		Button2:       &ev.Button2,
+		Button3:       &ev.Button3,
+		Shift:         &ev.Shift,
+		Ctrl:          &ev.Ctrl,
+		Alt:           &ev.Alt,
+		Escape:        &ev.Escape,
+		Enter:         &ev.Enter,
+		Up:            &ev.Up,
+		Down:          &ev.Down,
+		Left:          &ev.Left,
+		Right:         &ev.Right,
+		WindowResized: &ev.WindowResized,
+	}
+}
+
+// NewFrame captures an event state.
+func NewFrame(ev *event.State, elapsed time.Duration) Frame {
+	f := Frame{
+		Time:    elapsed,
+		CursorX: ev.CursorX,
+		CursorY: ev.CursorY,
+		WheelX:  ev.WheelX,
+		WheelY:  ev.WheelY,
+		Keys:    ev.KeysDown(false),
+	}
+	for flag, field := range flagFields(ev) {
+		if *field {
+			f.Flags |= flag
+		}
+	}
+	return f
+}
+
+// State rebuilds the event state of the frame.
+func (f Frame) State() *event.State {
+	ev := event.NewState()
+	ev.CursorX, ev.CursorY = f.CursorX, f.CursorY
+	ev.WheelX, ev.WheelY = f.WheelX, f.WheelY
+	for flag, field := range flagFields(ev) {
+		*field = f.Flags&flag != 0
+	}
+	for _, key := range f.Keys {
+		ev.SetKeyDown(key, true)
+	}
+	return ev
+}
+
+// Write the session in its compact file format (gzipped gob).
+func (s *Session) Write(w io.Writer) error {
+	zw := gzip.NewWriter(w)
+	if err := gob.NewEncoder(zw).Encode(s); err != nil {
+		zw.Close()
+		return err
+	}
+	return zw.Close()
+}
+
+// Save the session to a file.
+func (s *Session) Save(filename string) error {
+	fh, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+
+	if err := s.Write(fh); err != nil {
+		fh.Close()
+		return err
+	}
+	return fh.Close()
+}
+
+// Read a session from its file format.
+func Read(r io.Reader) (*Session, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	var s Session
+	if err := gob.NewDecoder(zr).Decode(&s); err != nil {
+		return nil, err
+	}
+	if s.Version != Version {
+		return nil, fmt.Errorf("replay: unsupported session version %d", s.Version)
+	}
+	return &s, nil
+}
+
+// Load a session from a file.
+func Load(filename string) (*Session, error) {
+	fh, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer fh.Close()
+	return Read(fh)
+}
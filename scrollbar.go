@@ -22,7 +22,11 @@ type ScrollBar struct {
 	trough *Frame
 	slider *Frame
 
-	// Configurable scroll ranges.
+	// Horizontal scrollbars scroll left and right.
+	Horizontal bool
+
+	// Configurable scroll ranges. The slider moves by Step pixels for each
+	// click of the arrow buttons and each notch of the mouse wheel.
 	Min   int
 	Max   int
 	Step  int
@@ -35,7 +39,7 @@ type ScrollBar struct {
 
 	// Drag/drop state.
 	dragging    bool         // mouse down on slider
-	scrollPx    int          // px from the top where the slider is placed
+	scrollPx    int          // px from the top (or left) where the slider is placed
 	dragStart   render.Point // where the mouse was on click
 	wasScrollPx int
 
@@ -45,12 +49,13 @@ type ScrollBar struct {
 // NewScrollBar creates a new ScrollBar.
 func NewScrollBar(config ScrollBar) *ScrollBar {
 	w := &ScrollBar{
-		Frame:    NewFrame("Scrollbar Frame"),
-		Variable: config.Variable,
-		style:    &style.DefaultButton,
-		Min:      config.Min,
-		Max:      config.Max,
-		Step:     config.Step,
+		Frame:      NewFrame("Scrollbar Frame"),
+		Variable:   config.Variable,
+		style:      &style.DefaultButton,
+		Horizontal: config.Horizontal,
+		Min:        config.Min,
+		Max:        config.Max,
+		Step:       config.Step,
 	}
 
 	if w.Max == 0 {
@@ -113,16 +118,34 @@ func (w *ScrollBar) Compute(e render.Engine) {
 
 // setup the UI components and event handlers.
 func (w *ScrollBar) setup() {
-	w.Configure(Config{
-		Width: scrollWidth,
-	})
+	var (
+		// Labels and placement of the parts along the scrollbar.
+		upText   = "^"
+		downText = "v"
+		side     = N
+		slider   = Config{
+			Width:  scrollWidth - w.BoxThickness(w.style.BorderSize),
+			Height: scrollbarHeight,
+		}
+	)
+	if w.Horizontal {
+		upText, downText, side = "<", ">", W
+		slider.Width, slider.Height = slider.Height, slider.Width
+		w.Configure(Config{
+			Height: scrollWidth,
+		})
+	} else {
+		w.Configure(Config{
+			Width: scrollWidth,
+		})
+	}
 
 	// The trough that holds the slider.
 	w.trough = NewFrame("Trough")
 
 	// Up button
 	upBtn := NewButton("Up", NewLabel(Label{
-		Text: "^",
+		Text: upText,
 	}))
 	upBtn.Handle(MouseDown, func(ed EventData) error {
 		w.everyTick = func() {
@@ -130,9 +153,7 @@ func (w *ScrollBar) setup() {
 			if w.scrollPx < 0 {
 				w.scrollPx = 0
 			}
-			w.trough.Place(w.slider, Place{
-				Top: w.scrollPx,
-			})
+			w.placeSlider()
 			w.sendScrollEvent()
 		}
 		return nil
@@ -148,8 +169,8 @@ func (w *ScrollBar) setup() {
 		BorderSize:  w.style.BorderSize,
 		BorderStyle: BorderStyle(w.style.BorderStyle),
 		Background:  w.style.Background,
-		Width:       scrollWidth - w.BoxThickness(w.style.BorderSize),
-		Height:      scrollbarHeight,
+		Width:       slider.Width,
+		Height:      slider.Height,
 	})
 
 	// Slider events
@@ -179,35 +200,34 @@ func (w *ScrollBar) setup() {
 				delta  = w.dragStart.Compare(ed.Point)
 				moveTo = w.wasScrollPx + delta.Y
 			)
+			if w.Horizontal {
+				moveTo = w.wasScrollPx + delta.X
+			}
 
 			if moveTo < 0 {
 				moveTo = 0
-			} else if moveTo > w.trough.height-w.slider.height {
-				moveTo = w.trough.height - w.slider.height
+			} else if moveTo > w.travel() {
+				moveTo = w.travel()
 			}
 
 			fmt.Printf("delta drag: %s\n", delta)
 			w.scrollPx = moveTo
-			w.trough.Place(w.slider, Place{
-				Top: w.scrollPx,
-			})
+			w.placeSlider()
 			w.sendScrollEvent()
 		}
 		return nil
 	})
 
 	downBtn := NewButton("Down", NewLabel(Label{
-		Text: "v",
+		Text: downText,
 	}))
 	downBtn.Handle(MouseDown, func(ed EventData) error {
 		w.everyTick = func() {
 			w.scrollPx += w.Step
-			if w.scrollPx > w.trough.height-w.slider.height {
-				w.scrollPx = w.trough.height - w.slider.height
+			if w.scrollPx > w.travel() {
+				w.scrollPx = w.travel()
 			}
-			w.trough.Place(w.slider, Place{
-				Top: w.scrollPx,
-			})
+			w.placeSlider()
 			w.sendScrollEvent()
 		}
 		return nil
@@ -217,22 +237,38 @@ func (w *ScrollBar) setup() {
 		return nil
 	})
 
+	// Mouse wheel events over any part of the scrollbar. Capturing the
+	// wheel's Scroll event keeps it from the caller's own Scroll handlers:
+	// they get the Scroll event sent after the slider has moved instead.
+	// A horizontal scrollbar follows a sideways wheel, or else the usual one.
+	w.HandleCapture(Scroll, func(ed EventData) error {
+		var notches = -ed.WheelY
+		if w.Horizontal && ed.WheelX != 0 {
+			notches = ed.WheelX
+		}
+
+		if notches != 0 {
+			w.ScrollBy(notches)
+			return ErrStopPropagation
+		}
+		return nil
+	})
+
 	w.Frame.Pack(upBtn, Pack{
-		Side:  N,
-		FillX: true,
+		Side:  side,
+		FillX: !w.Horizontal,
+		FillY: w.Horizontal,
 	})
 	w.Frame.Pack(w.trough, Pack{
-		Side:   N,
+		Side:   side,
 		Fill:   true,
 		Expand: true,
 	})
-	w.trough.Place(w.slider, Place{
-		Top:  w.scrollPx,
-		Left: 0,
-	})
+	w.placeSlider()
 	w.Frame.Pack(downBtn, Pack{
-		Side:  N,
-		FillX: true,
+		Side:  side,
+		FillX: !w.Horizontal,
+		FillY: w.Horizontal,
 	})
 }
 
@@ -241,11 +277,73 @@ func (w *ScrollBar) Present(e render.Engine, p render.Point) {
 	w.Frame.Present(e, p)
 }
 
-func (w *ScrollBar) sendScrollEvent() {
-	var fraction float64
-	if w.scrollPx > 0 {
-		fraction = float64(w.scrollPx) / (float64(w.trough.height) - float64(w.slider.height))
+// ScrollBy moves the slider by a number of Steps: positive to scroll down
+// (or right) and negative to scroll up. It sends a Scroll event if the
+// slider moved.
+func (w *ScrollBar) ScrollBy(steps int) {
+	var scrollPx = w.scrollPx + steps*w.Step
+	if scrollPx > w.travel() {
+		scrollPx = w.travel()
+	}
+	if scrollPx < 0 {
+		scrollPx = 0
+	}
+
+	if scrollPx != w.scrollPx {
+		w.scrollPx = scrollPx
+		w.placeSlider()
+		w.sendScrollEvent()
+	}
+}
+
+// Fraction returns the position of the slider, from 0 at the top (or left)
+// to 1 at the bottom (or right).
+func (w *ScrollBar) Fraction() float64 {
+	if w.scrollPx <= 0 || w.travel() <= 0 {
+		return 0
+	}
+	return float64(w.scrollPx) / float64(w.travel())
+}
+
+// SetFraction moves the slider to a position between 0 and 1, like the
+// Fraction, without sending a Scroll event. It lets the scrolled widget
+// keep its scrollbar in sync when it scrolls by itself.
+func (w *ScrollBar) SetFraction(v float64) {
+	if v < 0 {
+		v = 0
+	} else if v > 1 {
+		v = 1
+	}
+
+	if scrollPx := int(v*float64(w.travel()) + 0.5); scrollPx != w.scrollPx {
+		w.scrollPx = scrollPx
+		w.placeSlider()
+	}
+}
+
+// travel returns how far the slider moves along the trough, in pixels.
+func (w *ScrollBar) travel() int {
+	if w.Horizontal {
+		return w.trough.width - w.slider.width
 	}
+	return w.trough.height - w.slider.height
+}
+
+// placeSlider places the slider at scrollPx in the trough.
+func (w *ScrollBar) placeSlider() {
+	if w.Horizontal {
+		w.trough.Place(w.slider, Place{
+			Left: w.scrollPx,
+		})
+		return
+	}
+	w.trough.Place(w.slider, Place{
+		Top: w.scrollPx,
+	})
+}
+
+func (w *ScrollBar) sendScrollEvent() {
+	var fraction = w.Fraction()
 	w.Event(Scroll, EventData{
 		ScrollFraction: fraction,
 		ScrollUnits:    int(fraction * float64(w.Max)),
@@ -0,0 +1,50 @@
+package ui_test
+
+import (
+	"testing"
+
+	"git.kirsle.net/go/render"
+	"git.kirsle.net/go/render/event"
+	"git.kirsle.net/go/ui"
+	"git.kirsle.net/go/ui/softrender"
+)
+
+// A horizontal ScrollBar follows the sideways mouse wheel.
+func TestScrollBarHorizontalWheel(t *testing.T) {
+	var (
+		engine = softrender.New(300, 100)
+		s      = ui.NewSupervisor()
+		bar    = ui.NewScrollBar(ui.ScrollBar{Horizontal: true})
+	)
+	bar.Resize(render.NewRect(200, 20))
+	bar.Compute(engine)
+	bar.Supervise(s)
+	s.Add(bar)
+
+	ev := event.NewState()
+	ev.CursorX, ev.CursorY = 100, 10
+	s.Loop(ev)
+
+	ev.WheelX = 1
+	s.Loop(ev)
+	ev.WheelX = 0
+	if bar.Fraction() == 0 {
+		t.Fatalf("expected the wheel to scroll right")
+	}
+
+	// Scroll back with the sideways wheel, then right with the usual one.
+	var right = bar.Fraction()
+	ev.WheelX = -1
+	s.Loop(ev)
+	ev.WheelX = 0
+	if bar.Fraction() != 0 {
+		t.Errorf("expected the wheel to scroll back left, got %f", bar.Fraction())
+	}
+
+	ev.WheelY = -1
+	s.Loop(ev)
+	ev.WheelY = 0
+	if bar.Fraction() != right {
+		t.Errorf("expected the vertical wheel to scroll right to %f, got %f", right, bar.Fraction())
+	}
+}
@@ -0,0 +1,339 @@
+package ui
+
+import (
+	"fmt"
+
+	"git.kirsle.net/go/render"
+)
+
+// ScrollFrame is a container that scrolls its content by pixels, showing a
+// vertical and a horizontal ScrollBar when the content is taller or wider
+// than the ScrollFrame. The content is clipped to the ScrollFrame.
+//
+// Pack, Place, Grid or Flex the widgets into the ScrollFrame like into a
+// Frame, and give the ScrollFrame a size with Configure (Width and Height) or
+// by packing it to Fill its parent; it doesn't grow to fit its content.
+type ScrollFrame struct {
+	*Frame
+
+	// Content is the frame being scrolled, which the ScrollFrame's Pack,
+	// Place, Grid and Flex add the widgets to. It grows to fit them and fills
+	// at least the visible area.
+	Content *Frame
+
+	// Step is the number of pixels to scroll by for each notch of the mouse
+	// wheel.
+	Step int
+
+	supervisor *Supervisor
+	viewport   *Frame
+	vbar       *ScrollBar
+	hbar       *ScrollBar
+	view       render.Rect  // size of the visible area
+	offset     render.Point // scrolled position of the content
+	scrolled   bool         // the offset changed since the bars were moved
+}
+
+// NewScrollFrame creates a new ScrollFrame.
+func NewScrollFrame(name string) *ScrollFrame {
+	w := &ScrollFrame{
+		Frame:    NewFrame(name),
+		Content:  NewFrame(name + " Content"),
+		Step:     20,
+		viewport: NewFrame(name + " Viewport"),
+		vbar:     NewScrollBar(ScrollBar{}),
+		hbar:     NewScrollBar(ScrollBar{Horizontal: true}),
+	}
+
+	w.IDFunc(func() string {
+		return fmt.Sprintf("ScrollFrame<%s>", name)
+	})
+
+	w.setup()
+	return w
+}
+
+// setup the UI components and event handlers.
+func (w *ScrollFrame) setup() {
+	w.viewport.SetClipped(true)
+	w.viewport.Place(w.Content, Place{})
+	w.Frame.Place(w.viewport, Place{})
+	w.Frame.Place(w.vbar, Place{})
+	w.Frame.Place(w.hbar, Place{})
+	w.vbar.Hide()
+	w.hbar.Hide()
+
+	// Dragging the scrollbars scrolls the content.
+	w.vbar.Handle(Scroll, func(ed EventData) error {
+		w.setOffset(render.NewPoint(w.offset.X, w.scrollRange(ed.ScrollFraction, w.maxOffset().Y)))
+		return nil
+	})
+	w.hbar.Handle(Scroll, func(ed EventData) error {
+		w.setOffset(render.NewPoint(w.scrollRange(ed.ScrollFraction, w.maxOffset().X), w.offset.Y))
+		return nil
+	})
+
+	// The mouse wheel over the content. Scrollable widgets inside it, like
+	// a ListBox, get the wheel first.
+	w.HandleBubble(Scroll, func(ed EventData) error {
+		if ed.WheelX == 0 && ed.WheelY == 0 {
+			return nil
+		}
+
+		var before = w.offset
+		w.ScrollBy(render.NewPoint(
+			ed.WheelX*w.Step,
+			-ed.WheelY*w.Step,
+		))
+		if w.offset != before {
+			return ErrStopPropagation
+		}
+		return nil
+	})
+}
+
+// Supervise the ScrollFrame. This is necessary for the mouse wheel over its
+// content and for the events of its scrollbars.
+func (w *ScrollFrame) Supervise(s *Supervisor) {
+	w.supervisor = s
+	w.vbar.Supervise(s)
+	w.hbar.Supervise(s)
+	s.Add(w)
+}
+
+// Pack a widget into the scrolled content.
+func (w *ScrollFrame) Pack(child Widget, config ...Pack) {
+	w.Content.Pack(child, config...)
+}
+
+// Place a widget into the scrolled content.
+func (w *ScrollFrame) Place(child Widget, config Place) {
+	w.Content.Place(child, config)
+}
+
+// Grid a widget into the scrolled content.
+func (w *ScrollFrame) Grid(child Widget, config Grid) {
+	w.Content.Grid(child, config)
+}
+
+// GridRow configures a row of the scrolled content's grid.
+func (w *ScrollFrame) GridRow(row int, config GridTrack) {
+	w.Content.GridRow(row, config)
+}
+
+// GridColumn configures a column of the scrolled content's grid.
+func (w *ScrollFrame) GridColumn(column int, config GridTrack) {
+	w.Content.GridColumn(column, config)
+}
+
+// Ungrid a widget from the scrolled content.
+func (w *ScrollFrame) Ungrid(child Widget) bool {
+	return w.Content.Ungrid(child)
+}
+
+// Flex a widget into the scrolled content.
+func (w *ScrollFrame) Flex(child Widget, config Flex) {
+	w.Content.Flex(child, config)
+}
+
+// SetFlexLayout sets the flex layout of the scrolled content.
+func (w *ScrollFrame) SetFlexLayout(v FlexLayout) {
+	w.Content.SetFlexLayout(v)
+}
+
+// FlexLayout returns the flex layout of the scrolled content.
+func (w *ScrollFrame) FlexLayout() FlexLayout {
+	return w.Content.FlexLayout()
+}
+
+// Unflex a widget from the scrolled content.
+func (w *ScrollFrame) Unflex(child Widget) bool {
+	return w.Content.Unflex(child)
+}
+
+// Unpack a widget from the scrolled content.
+func (w *ScrollFrame) Unpack(child Widget) bool {
+	return w.Content.Unpack(child)
+}
+
+// Offset returns the scrolled position: the point of the content shown at
+// the top left corner of the ScrollFrame.
+func (w *ScrollFrame) Offset() render.Point {
+	return w.offset
+}
+
+// ScrollTo scrolls the content so the point is at the top left corner of
+// the ScrollFrame. The next Compute keeps it within the content, so it may
+// be called before the content was laid out.
+func (w *ScrollFrame) ScrollTo(p render.Point) {
+	w.setOffset(p)
+	w.scrolled = true
+}
+
+// ScrollBy scrolls the content by a number of pixels: positive to scroll
+// down and right, negative to scroll up and left. It stops at the edges of
+// the content as it was laid out by the last Compute.
+func (w *ScrollFrame) ScrollBy(delta render.Point) {
+	w.ScrollTo(w.clampOffset(render.NewPoint(w.offset.X+delta.X, w.offset.Y+delta.Y)))
+}
+
+// EnsureVisible scrolls the least needed to show a widget inside the content,
+// as it was laid out by the last Compute. A widget larger than the visible
+// area shows its top left corner. Returns false if the widget isn't in the
+// ScrollFrame's content.
+func (w *ScrollFrame) EnsureVisible(child Widget) bool {
+	var P = child.Point()
+	for node := child; ; {
+		parent, ok := node.Parent()
+		if !ok || parent == nil {
+			return false
+		} else if parent == Widget(w.Content) {
+			break
+		}
+
+		P.Add(parent.Point())
+		P.Add(render.NewPoint(parent.BoxThickness(1), parent.BoxThickness(1)))
+		node = parent
+	}
+
+	var (
+		S      = child.Size()
+		offset = w.offset
+	)
+	offset.X = scrollIntoView(offset.X, P.X, S.W, w.view.W)
+	offset.Y = scrollIntoView(offset.Y, P.Y, S.H, w.view.H)
+	w.ScrollTo(offset)
+	return true
+}
+
+// scrollIntoView returns the offset that shows the span from start to
+// start+size in an area of the length, moving the least from the offset.
+func scrollIntoView(offset, start, size, length int) int {
+	if start < offset || size > length {
+		return start
+	} else if start+size > offset+length {
+		return start + size - length
+	}
+	return offset
+}
+
+// Compute the size of the content and lay out the scrollbars.
+func (w *ScrollFrame) Compute(e render.Engine) {
+	// The content's own size, not the one it was stretched to before.
+	w.Content.fixedSize = false
+	w.Content.Compute(e)
+
+	var (
+		S       = w.Size()
+		inner   = render.NewRect(S.W-w.BoxThickness(2), S.H-w.BoxThickness(2))
+		content = w.Content.Size()
+		needV   = content.H > inner.H
+		needH   = content.W > inner.W
+	)
+
+	// A scrollbar takes space from the other direction.
+	if needV && !needH {
+		needH = content.W > inner.W-scrollWidth
+	} else if needH && !needV {
+		needV = content.H > inner.H-scrollWidth
+	}
+
+	w.view = inner
+	if needV {
+		w.view.W -= scrollWidth
+	}
+	if needH {
+		w.view.H -= scrollWidth
+	}
+
+	// Stretch the content to fill the visible area.
+	if content.W < w.view.W || content.H < w.view.H {
+		w.Content.Resize(render.NewRect(
+			maxInt(content.W, w.view.W),
+			maxInt(content.H, w.view.H),
+		))
+		w.Content.Compute(e)
+	}
+
+	// Keep the content in view, e.g. after it shrank.
+	if offset := w.clampOffset(w.offset); offset != w.offset {
+		w.setOffset(offset)
+		w.scrolled = true
+	}
+
+	w.viewport.Resize(w.view)
+	w.viewport.Place(w.Content, Place{
+		Point: render.NewPoint(-w.offset.X, -w.offset.Y),
+	})
+
+	w.layoutBar(w.vbar, needV, render.Rect{X: w.view.W, W: scrollWidth, H: w.view.H})
+	w.layoutBar(w.hbar, needH, render.Rect{Y: w.view.H, W: w.view.W, H: scrollWidth})
+	w.Frame.computePlaced(e)
+
+	// Move the sliders to where the ScrollFrame scrolled by itself.
+	if w.scrolled {
+		var max = w.maxOffset()
+		if max.Y > 0 {
+			w.vbar.SetFraction(float64(w.offset.Y) / float64(max.Y))
+			computeChild(w.vbar, e)
+		}
+		if max.X > 0 {
+			w.hbar.SetFraction(float64(w.offset.X) / float64(max.X))
+			computeChild(w.hbar, e)
+		}
+		w.scrolled = false
+	}
+
+	// Call the BaseWidget Compute in case we have subscribers.
+	w.BaseWidget.Compute(e)
+}
+
+// layoutBar shows a scrollbar in its rect, or hides it.
+func (w *ScrollFrame) layoutBar(bar *ScrollBar, show bool, R render.Rect) {
+	if !show {
+		bar.Hide()
+		return
+	}
+
+	bar.Show()
+	bar.Resize(render.NewRect(R.W, R.H))
+	w.Frame.Place(bar, Place{
+		Point: R.Point(),
+	})
+}
+
+// Present the ScrollFrame.
+func (w *ScrollFrame) Present(e render.Engine, P render.Point) {
+	w.Frame.Present(e, P)
+}
+
+// maxOffset returns how far the content scrolls in each direction.
+func (w *ScrollFrame) maxOffset() render.Point {
+	content := w.Content.Size()
+	return render.NewPoint(
+		maxInt(content.W-w.view.W, 0),
+		maxInt(content.H-w.view.H, 0),
+	)
+}
+
+// clampOffset keeps an offset within the content.
+func (w *ScrollFrame) clampOffset(p render.Point) render.Point {
+	max := w.maxOffset()
+	return render.NewPoint(
+		minInt(maxInt(p.X, 0), max.X),
+		minInt(maxInt(p.Y, 0), max.Y),
+	)
+}
+
+// scrollRange converts a scrollbar fraction into an offset up to max.
+func (w *ScrollFrame) scrollRange(fraction float64, max int) int {
+	return int(fraction*float64(max) + 0.5)
+}
+
+// setOffset scrolls the content to an offset.
+func (w *ScrollFrame) setOffset(p render.Point) {
+	if p != w.offset {
+		w.offset = p
+		w.Invalidate()
+	}
+}
@@ -0,0 +1,92 @@
+package ui_test
+
+import (
+	"testing"
+
+	"git.kirsle.net/go/render"
+	"git.kirsle.net/go/render/event"
+	"git.kirsle.net/go/ui"
+	"git.kirsle.net/go/ui/softrender"
+)
+
+func TestScrollFrame(t *testing.T) {
+	var (
+		engine  = softrender.New(200, 200)
+		s       = ui.NewSupervisor()
+		ev      = event.NewState()
+		scroll  = ui.NewScrollFrame("Settings")
+		rows    = make([]*ui.Frame, 10)
+		hovered = -1
+	)
+	scroll.Configure(ui.Config{
+		Width:      100,
+		Height:     100,
+		Background: testBackground,
+	})
+
+	// 10 rows of 30px, alternating colors.
+	for i := range rows {
+		var (
+			i   = i
+			row = ui.NewFrame("Row")
+		)
+		row.Configure(ui.Config{
+			Width:      80,
+			Height:     30,
+			Background: testHighlight,
+		})
+		if i%2 == 1 {
+			row.SetBackground(testForeground)
+		}
+		row.Handle(ui.MouseOver, func(ed ui.EventData) error {
+			hovered = i
+			return nil
+		})
+		scroll.Pack(row, ui.Pack{Side: ui.N})
+		s.Add(row)
+		rows[i] = row
+	}
+	scroll.Supervise(s)
+	scroll.Compute(engine)
+
+	// The view is 80px wide next to the vertical scrollbar.
+	if !scroll.EnsureVisible(rows[9]) || scroll.Offset() != render.NewPoint(0, 200) {
+		t.Errorf("expected the last row to scroll to 0,200, got %s", scroll.Offset())
+	}
+	if scroll.EnsureVisible(ui.NewFrame("Elsewhere")) {
+		t.Errorf("expected EnsureVisible to fail outside the ScrollFrame")
+	}
+	scroll.ScrollTo(render.NewPoint(0, 60))
+	scroll.Compute(engine)
+
+	// Row 2 is at the top, the rows below the ScrollFrame are clipped.
+	engine.Clear(testForeground)
+	scroll.Present(engine, scroll.Point())
+	if !pixelIs(engine, 10, 5, testHighlight) || !pixelIs(engine, 10, 130, testForeground) {
+		t.Errorf("expected row 2 at the top and nothing drawn below")
+	}
+
+	// The mouse hits the visible rows only.
+	ev.CursorX, ev.CursorY = 10, 150
+	s.Loop(ev)
+	if hovered != -1 {
+		t.Errorf("expected no row under the ScrollFrame, got %d", hovered)
+	}
+	ev.CursorX, ev.CursorY = 10, 5
+	s.Loop(ev)
+	if hovered != 2 {
+		t.Errorf("expected to hover row 2, got %d", hovered)
+	}
+
+	// The mouse wheel scrolls up, and no further than the top.
+	ev.WheelY = 1
+	s.Loop(ev)
+	ev.WheelY = 0
+	if scroll.Offset() != render.NewPoint(0, 40) {
+		t.Errorf("expected the wheel to scroll to 0,40, got %s", scroll.Offset())
+	}
+	scroll.ScrollBy(render.NewPoint(0, -500))
+	if scroll.Offset() != render.NewPoint(0, 0) {
+		t.Errorf("expected to stop at the top, got %s", scroll.Offset())
+	}
+}
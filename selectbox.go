@@ -78,6 +78,7 @@ func NewSelectBox(name string, withLabel Label) *SelectBox {
 	w.IDFunc(func() string {
 		return fmt.Sprintf("SelectBox<%s>", name)
 	})
+	w.SetFocusable(true)
 
 	w.setup()
 	return w
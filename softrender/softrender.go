@@ -0,0 +1,278 @@
+// Package softrender is a headless render.Engine that draws into an
+// image.RGBA in memory.
+//
+// It needs no display or GPU, so it can run real Compute and Present cycles
+// of a UI in `go test` on a CI machine, and take screenshots of widgets for
+// documentation. Input comes from event states queued by the caller, and
+// time only moves forward when Delay is called.
+package softrender
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"os"
+
+	"git.kirsle.net/go/render"
+	"git.kirsle.net/go/render/event"
+)
+
+// Engine is a software rasterizer implementing render.Engine.
+type Engine struct {
+	title  string
+	width  int
+	height int
+	canvas *image.RGBA
+
+	// Scripted input.
+	queue []*event.State
+	state *event.State
+
+	// Simulated clock in milliseconds, advanced by Delay.
+	ticks uint32
+
+	// Number of frames presented.
+	frames int
+
+	textures map[string]*Texture
+	fonts    *fontCache
+}
+
+var _ render.Engine = (*Engine)(nil)
+
+// New creates a headless engine with a canvas of the given size.
+func New(width, height int) *Engine {
+	e := &Engine{
+		width:    width,
+		height:   height,
+		state:    event.NewState(),
+		textures: map[string]*Texture{},
+		fonts:    newFontCache(),
+	}
+	e.canvas = image.NewRGBA(image.Rect(0, 0, width, height))
+	return e
+}
+
+// Setup the engine. The canvas is ready after New, so this does nothing.
+func (e *Engine) Setup() error {
+	return nil
+}
+
+// SetTitle sets the window title, which is only remembered.
+func (e *Engine) SetTitle(title string) {
+	e.title = title
+}
+
+// Title returns the window title.
+func (e *Engine) Title() string {
+	return e.title
+}
+
+// Resize the canvas, as if the window was resized. The canvas is cleared and
+// the next Poll reports WindowResized.
+func (e *Engine) Resize(width, height int) {
+	e.width, e.height = width, height
+	e.canvas = image.NewRGBA(image.Rect(0, 0, width, height))
+
+	ev := *e.state
+	ev.WindowResized = true
+	e.queue = append([]*event.State{&ev}, e.queue...)
+}
+
+// WindowSize returns the size of the canvas.
+func (e *Engine) WindowSize() (w, h int) {
+	return e.width, e.height
+}
+
+// Queue event states to be returned by the following calls to Poll, one per
+// call, as if the user had moved the mouse and pressed keys.
+func (e *Engine) Queue(states ...*event.State) {
+	e.queue = append(e.queue, states...)
+}
+
+// Poll returns the next queued event state. When the queue is empty, the
+// last state is returned again (with WindowResized cleared), as when the
+// user isn't doing anything.
+func (e *Engine) Poll() (*event.State, error) {
+	if len(e.queue) > 0 {
+		e.state = e.queue[0]
+		e.queue = e.queue[1:]
+		return e.state, nil
+	}
+
+	e.state.WindowResized = false
+	return e.state, nil
+}
+
+// GetTicks returns the simulated number of milliseconds since the engine
+// started.
+func (e *Engine) GetTicks() uint32 {
+	return e.ticks
+}
+
+// Delay advances the simulated clock. It returns right away rather than
+// sleeping.
+func (e *Engine) Delay(ms uint32) {
+	e.ticks += ms
+}
+
+// Present counts a frame; the canvas always holds the latest drawing.
+func (e *Engine) Present() error {
+	e.frames++
+	return nil
+}
+
+// Frames returns the number of times Present was called.
+func (e *Engine) Frames() int {
+	return e.frames
+}
+
+// Loop polls for input and presents a frame.
+func (e *Engine) Loop() error {
+	if _, err := e.Poll(); err != nil {
+		return err
+	}
+	return e.Present()
+}
+
+// Teardown frees the textures and fonts.
+func (e *Engine) Teardown() {
+	e.FreeTextures()
+	e.fonts = newFontCache()
+}
+
+// Image returns the canvas.
+func (e *Engine) Image() *image.RGBA {
+	return e.canvas
+}
+
+// WritePNG encodes the canvas as a PNG image.
+func (e *Engine) WritePNG(w io.Writer) error {
+	return png.Encode(w, e.canvas)
+}
+
+// SavePNG writes the canvas to a PNG file.
+func (e *Engine) SavePNG(filename string) error {
+	fh, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+
+	if err := e.WritePNG(fh); err != nil {
+		fh.Close()
+		return err
+	}
+	return fh.Close()
+}
+
+// Clear the canvas to a color.
+func (e *Engine) Clear(c render.Color) {
+	draw.Draw(e.canvas, e.canvas.Bounds(), image.NewUniform(toColor(c)), image.Point{}, draw.Src)
+}
+
+// DrawPoint draws a single pixel.
+func (e *Engine) DrawPoint(c render.Color, p render.Point) {
+	e.fill(c, image.Rect(p.X, p.Y, p.X+1, p.Y+1))
+}
+
+// DrawLine draws a line between two points, inclusive.
+func (e *Engine) DrawLine(c render.Color, a, b render.Point) {
+	if c.Alpha == 0 {
+		return
+	}
+
+	var (
+		dx  = absInt(b.X - a.X)
+		dy  = -absInt(b.Y - a.Y)
+		sx  = 1
+		sy  = 1
+		err = dx + dy
+		x   = a.X
+		y   = a.Y
+	)
+	if a.X > b.X {
+		sx = -1
+	}
+	if a.Y > b.Y {
+		sy = -1
+	}
+
+	// Bresenham's line algorithm.
+	for {
+		e.DrawPoint(c, render.NewPoint(x, y))
+		if x == b.X && y == b.Y {
+			return
+		}
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			x += sx
+		}
+		if e2 <= dx {
+			err += dx
+			y += sy
+		}
+	}
+}
+
+// DrawRect draws the outline of a rectangle.
+func (e *Engine) DrawRect(c render.Color, r render.Rect) {
+	if r.W <= 0 || r.H <= 0 {
+		return
+	}
+
+	var (
+		x2 = r.X + r.W
+		y2 = r.Y + r.H
+	)
+	e.fill(c, image.Rect(r.X, r.Y, x2, r.Y+1))
+	if r.H > 1 {
+		e.fill(c, image.Rect(r.X, y2-1, x2, y2))
+	}
+	if r.H > 2 {
+		e.fill(c, image.Rect(r.X, r.Y+1, r.X+1, y2-1))
+		if r.W > 1 {
+			e.fill(c, image.Rect(x2-1, r.Y+1, x2, y2-1))
+		}
+	}
+}
+
+// DrawBox draws a filled rectangle.
+func (e *Engine) DrawBox(c render.Color, r render.Rect) {
+	if r.W <= 0 || r.H <= 0 {
+		return
+	}
+	e.fill(c, image.Rect(r.X, r.Y, r.X+r.W, r.Y+r.H))
+}
+
+// fill blends a color over an area of the canvas.
+func (e *Engine) fill(c render.Color, r image.Rectangle) {
+	if c.Alpha == 0 {
+		return
+	}
+
+	var op = draw.Over
+	if c.Alpha == 255 {
+		op = draw.Src
+	}
+	draw.Draw(e.canvas, r, image.NewUniform(toColor(c)), image.Point{}, op)
+}
+
+// toColor converts a render.Color to a non-premultiplied color.
+func toColor(c render.Color) color.NRGBA {
+	return color.NRGBA{
+		R: c.Red,
+		G: c.Green,
+		B: c.Blue,
+		A: c.Alpha,
+	}
+}
+
+func absInt(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
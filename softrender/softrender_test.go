@@ -0,0 +1,150 @@
+package softrender
+
+import (
+	"image"
+	"image/color"
+	"os"
+	"testing"
+
+	"git.kirsle.net/go/render"
+	"git.kirsle.net/go/render/event"
+	"git.kirsle.net/go/ui"
+)
+
+var (
+	red   = render.RGBA(255, 0, 0, 255)
+	blue  = render.RGBA(0, 0, 255, 255)
+	white = render.RGBA(255, 255, 255, 255)
+)
+
+// expectPixel checks the color of a pixel on the canvas.
+func expectPixel(t *testing.T, e *Engine, x, y int, c render.Color) {
+	t.Helper()
+	got := color.NRGBAModel.Convert(e.Image().At(x, y)).(color.NRGBA)
+	if got != toColor(c) {
+		t.Errorf("pixel %d,%d: expected %v, got %v", x, y, toColor(c), got)
+	}
+}
+
+func TestDraw(t *testing.T) {
+	e := New(20, 20)
+	e.Clear(white)
+	e.DrawBox(red, render.Rect{X: 2, Y: 2, W: 4, H: 4})
+	e.DrawRect(blue, render.Rect{X: 10, Y: 10, W: 5, H: 5})
+	e.DrawLine(blue, render.NewPoint(0, 19), render.NewPoint(19, 19))
+
+	expectPixel(t, e, 2, 2, red)
+	expectPixel(t, e, 5, 5, red)
+	expectPixel(t, e, 6, 6, white)
+	expectPixel(t, e, 10, 10, blue)
+	expectPixel(t, e, 14, 14, blue)
+	expectPixel(t, e, 12, 12, white) // inside the outline
+	expectPixel(t, e, 19, 19, blue)
+
+	// Half transparent boxes blend over the canvas.
+	e.DrawBox(render.RGBA(0, 0, 0, 128), render.Rect{X: 0, Y: 0, W: 1, H: 1})
+	if c := e.Image().RGBAAt(0, 0); c.R < 120 || c.R > 135 {
+		t.Errorf("expected a blended grey pixel, got %v", c)
+	}
+
+	// Textures are scaled into the destination rect.
+	img := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	img.Set(0, 0, color.RGBA{0, 0, 255, 255})
+	tex, err := e.StoreTexture("swatch", img)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.Copy(tex, render.NewRect(2, 2), render.Rect{X: 0, Y: 10, W: 4, H: 4})
+	expectPixel(t, e, 1, 11, blue)
+	if _, err := e.LoadTexture("swatch"); err != nil || e.FreeTextures() != 1 {
+		t.Errorf("expected to load and free the texture")
+	}
+
+	// Freeing an old handle keeps the newer texture of the same name.
+	old, _ := e.StoreTexture("swatch", img)
+	newer, _ := e.StoreTexture("swatch", img)
+	old.Free()
+	if tex, err := e.LoadTexture("swatch"); err != nil || tex != newer {
+		t.Errorf("expected the newer texture to stay")
+	}
+	newer.Free()
+	if _, err := e.LoadTexture("swatch"); err == nil {
+		t.Errorf("expected the texture to be freed")
+	}
+}
+
+func TestPoll(t *testing.T) {
+	var (
+		e     = New(10, 10)
+		click = event.NewState()
+	)
+	click.CursorX, click.CursorY, click.Button1 = 5, 5, true
+	e.Queue(click)
+
+	if ev, _ := e.Poll(); ev != click {
+		t.Errorf("expected the queued event state")
+	}
+	if ev, _ := e.Poll(); ev != click {
+		t.Errorf("expected the last state again once the queue is empty")
+	}
+
+	e.Delay(16)
+	e.Delay(16)
+	if e.GetTicks() != 32 {
+		t.Errorf("expected 32 simulated ticks, got %d", e.GetTicks())
+	}
+}
+
+func TestRenderWidget(t *testing.T) {
+	frame := ui.NewFrame("Swatch")
+	frame.Configure(ui.Config{
+		Width:      30,
+		Height:     20,
+		Background: red,
+	})
+
+	label := ui.NewLabel(ui.Label{
+		Text: "Hello",
+		Font: render.Text{
+			FontFilename: "no such font.ttf",
+			Color:        blue,
+			Padding:      2,
+		},
+	})
+
+	e := RenderWidget(frame)
+	if w, h := e.WindowSize(); w != 30 || h != 20 {
+		t.Errorf("expected a 30x20 canvas, got %dx%d", w, h)
+	}
+	expectPixel(t, e, 15, 10, red)
+
+	// Without the font file, text is measured with the fallback face.
+	e = RenderWidget(label)
+	if size := label.Size(); size.W != 5*7+4 || size.H != 13+4 {
+		t.Errorf("expected the label sized by the fallback font, got %dx%d", size.W, size.H)
+	}
+
+	// TrueType fonts are loaded by file name.
+	label.Font.FontFilename = "../eg/DejaVuSans.ttf"
+	label.Font.Size = 20
+	RenderWidget(label)
+	if size := label.Size(); size.H <= 13+4 {
+		t.Errorf("expected a taller label with the 20pt font, got %dx%d", size.W, size.H)
+	}
+
+	// Or by the name they were registered under, also as the default font.
+	data, err := os.ReadFile("../eg/DejaVuSans.ttf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := RegisterFont("sans", data); err != nil {
+		t.Fatal(err)
+	}
+	defer func(name string) { DefaultFontFilename = name }(DefaultFontFilename)
+	DefaultFontFilename = "sans"
+	label.Font.FontFilename = ""
+	RenderWidget(label)
+	if size := label.Size(); size.H <= 13+4 {
+		t.Errorf("expected the registered default font, got %dx%d", size.W, size.H)
+	}
+}
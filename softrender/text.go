@@ -0,0 +1,193 @@
+package softrender
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	"os"
+	"sync"
+
+	"git.kirsle.net/go/render"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// Default font settings for text that doesn't name its own. Without a
+// DefaultFontFilename, such text is drawn with the FallbackFace: set it to
+// the name of a font registered with RegisterFont (or to a font file) to
+// draw it with a TrueType font.
+var (
+	DefaultFontFilename = ""
+	DefaultFontSize     = 14
+)
+
+// FallbackFace is used for text with no font, or whose font file can't be
+// found or parsed, so that a UI can still be laid out and drawn on a machine
+// without fonts.
+var FallbackFace font.Face = basicfont.Face7x13
+
+// Fonts registered in memory with RegisterFont, by name.
+var (
+	registry     = map[string][]byte{}
+	registryLock sync.RWMutex
+)
+
+// RegisterFont registers the bytes of a TrueType or OpenType font under a
+// name, so text with that FontFilename uses it without reading the file
+// (e.g. for a font embedded in a test binary).
+func RegisterFont(name string, data []byte) error {
+	if _, err := opentype.Parse(data); err != nil {
+		return fmt.Errorf("RegisterFont(%s): %s", name, err)
+	}
+
+	registryLock.Lock()
+	registry[name] = data
+	registryLock.Unlock()
+	return nil
+}
+
+// fontCache holds the fonts and font faces loaded by an Engine.
+type fontCache struct {
+	fonts map[string]*opentype.Font // nil if the font failed to load
+	faces map[faceKey]font.Face
+}
+
+type faceKey struct {
+	filename string
+	size     int
+}
+
+func newFontCache() *fontCache {
+	return &fontCache{
+		fonts: map[string]*opentype.Font{},
+		faces: map[faceKey]font.Face{},
+	}
+}
+
+// font loads a font by its filename, from the registry or the disk. Returns
+// nil if there is no such font, or no filename.
+func (c *fontCache) font(filename string) *opentype.Font {
+	if filename == "" {
+		return nil
+	} else if f, ok := c.fonts[filename]; ok {
+		return f
+	}
+
+	registryLock.RLock()
+	data, ok := registry[filename]
+	registryLock.RUnlock()
+	if !ok {
+		var err error
+		if data, err = os.ReadFile(filename); err != nil {
+			c.fonts[filename] = nil
+			return nil
+		}
+	}
+
+	f, err := opentype.Parse(data)
+	if err != nil {
+		f = nil
+	}
+	c.fonts[filename] = f
+	return f
+}
+
+// face returns the font face for a text's font and size.
+func (c *fontCache) face(text render.Text) font.Face {
+	var key = faceKey{
+		filename: text.FontFilename,
+		size:     text.Size,
+	}
+	if key.filename == "" {
+		key.filename = DefaultFontFilename
+	}
+	if key.size <= 0 {
+		key.size = DefaultFontSize
+	}
+
+	if face, ok := c.faces[key]; ok {
+		return face
+	}
+
+	var face = FallbackFace
+	if f := c.font(key.filename); f != nil {
+		if loaded, err := opentype.NewFace(f, &opentype.FaceOptions{
+			Size:    float64(key.size),
+			DPI:     72,
+			Hinting: font.HintingFull,
+		}); err == nil {
+			face = loaded
+		}
+	}
+
+	c.faces[key] = face
+	return face
+}
+
+// ComputeTextRect returns the size of a line of text.
+func (e *Engine) ComputeTextRect(text render.Text) (render.Rect, error) {
+	var (
+		face    = e.fonts.face(text)
+		metrics = face.Metrics()
+	)
+	return render.NewRect(
+		font.MeasureString(face, text.Text).Ceil(),
+		(metrics.Ascent + metrics.Descent).Ceil(),
+	), nil
+}
+
+// DrawText draws a line of text with its top left corner at the point,
+// with its shadow (offset by one pixel down and right) and stroke, if set.
+func (e *Engine) DrawText(text render.Text, p render.Point) error {
+	e.drawText(e.canvas, text, p)
+	return nil
+}
+
+// RenderText draws a line of text onto a new transparent image of its size.
+// The text's top left corner is at (0,0) and the image has a margin of one
+// pixel around it for the stroke and shadow. It can render the textures of
+// a ui.TextCache, for any engine.
+func (e *Engine) RenderText(text render.Text) (image.Image, error) {
+	rect, err := e.ComputeTextRect(text)
+	if err != nil {
+		return nil, err
+	}
+
+	img := image.NewRGBA(image.Rect(-1, -1, rect.W+1, rect.H+1))
+	e.drawText(img, text, render.Point{})
+	return img, nil
+}
+
+// drawText draws a line of text onto an image.
+func (e *Engine) drawText(dst draw.Image, text render.Text, p render.Point) {
+	var face = e.fonts.face(text)
+
+	write := func(c render.Color, dx, dy int) {
+		if c.Alpha == 0 {
+			return
+		}
+		d := font.Drawer{
+			Dst:  dst,
+			Src:  image.NewUniform(toColor(c)),
+			Face: face,
+			Dot:  fixed.P(p.X+dx, p.Y+dy+face.Metrics().Ascent.Ceil()),
+		}
+		d.DrawString(text.Text)
+	}
+
+	if text.Shadow != render.Invisible {
+		write(text.Shadow, 1, 1)
+	}
+	if text.Stroke != render.Invisible {
+		for dy := -1; dy <= 1; dy++ {
+			for dx := -1; dx <= 1; dx++ {
+				if dx != 0 || dy != 0 {
+					write(text.Stroke, dx, dy)
+				}
+			}
+		}
+	}
+	write(text.Color, 0, 0)
+}
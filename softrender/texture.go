@@ -0,0 +1,92 @@
+package softrender
+
+import (
+	"errors"
+	"image"
+	"image/draw"
+
+	"git.kirsle.net/go/render"
+	xdraw "golang.org/x/image/draw"
+)
+
+// Error messages for textures.
+var (
+	ErrTextureNotFound = errors.New("texture not found")
+)
+
+// Texture is an image stored with StoreTexture.
+type Texture struct {
+	engine *Engine
+	name   string
+	image  *image.RGBA
+}
+
+// Size returns the size of the texture.
+func (t *Texture) Size() render.Rect {
+	b := t.image.Bounds()
+	return render.NewRect(b.Dx(), b.Dy())
+}
+
+// Image returns the texture's image.
+func (t *Texture) Image() image.Image {
+	return t.image
+}
+
+// Free the texture from the engine, unless a newer texture has been stored
+// under its name since.
+func (t *Texture) Free() error {
+	if t.engine != nil && t.engine.textures[t.name] == t {
+		delete(t.engine.textures, t.name)
+	}
+	return nil
+}
+
+// StoreTexture copies an image into a named texture, replacing any texture
+// of the same name.
+func (e *Engine) StoreTexture(name string, img image.Image) (render.Texturer, error) {
+	b := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, b.Min, draw.Src)
+
+	t := &Texture{
+		engine: e,
+		name:   name,
+		image:  rgba,
+	}
+	e.textures[name] = t
+	return t, nil
+}
+
+// LoadTexture returns a texture stored earlier.
+func (e *Engine) LoadTexture(name string) (render.Texturer, error) {
+	if t, ok := e.textures[name]; ok {
+		return t, nil
+	}
+	return nil, ErrTextureNotFound
+}
+
+// Copy draws the src rect of a texture into the dst rect of the canvas,
+// scaling it to fit.
+func (e *Engine) Copy(t render.Texturer, src, dst render.Rect) {
+	if t == nil || dst.W <= 0 || dst.H <= 0 {
+		return
+	}
+
+	img := t.Image()
+	if img == nil {
+		return
+	}
+	xdraw.NearestNeighbor.Scale(
+		e.canvas, image.Rect(dst.X, dst.Y, dst.X+dst.W, dst.Y+dst.H),
+		img, image.Rect(src.X, src.Y, src.X+src.W, src.Y+src.H).Add(img.Bounds().Min),
+		xdraw.Over, nil,
+	)
+}
+
+// FreeTextures frees all of the stored textures and returns how many there
+// were.
+func (e *Engine) FreeTextures() int {
+	n := len(e.textures)
+	e.textures = map[string]*Texture{}
+	return n
+}
@@ -0,0 +1,31 @@
+package softrender
+
+import (
+	"image"
+
+	"git.kirsle.net/go/render"
+	"git.kirsle.net/go/ui"
+)
+
+// RenderWidget computes and presents a widget and its children on a new
+// engine with a canvas the size of the widget, which is returned.
+//
+// The canvas starts out transparent. The widget is drawn at the top left
+// corner regardless of its position in its parent.
+func RenderWidget(w ui.Widget) *Engine {
+	e := New(0, 0)
+	w.Compute(e)
+
+	size := w.Size()
+	e.width, e.height = size.W, size.H
+	e.canvas = image.NewRGBA(image.Rect(0, 0, size.W, size.H))
+
+	w.Present(e, render.Origin)
+	e.Present()
+	return e
+}
+
+// WidgetPNG renders a widget and its children to a PNG file.
+func WidgetPNG(w ui.Widget, filename string) error {
+	return RenderWidget(w).SavePNG(filename)
+}
@@ -2,7 +2,9 @@ package ui
 
 import (
 	"errors"
+	"sort"
 	"sync"
+	"time"
 
 	"git.kirsle.net/go/render"
 	"git.kirsle.net/go/render/event"
@@ -42,6 +44,41 @@ const (
 
 	// Form field events.
 	Change
+
+	// Keyboard focus events.
+	FocusIn  // the widget has received the keyboard focus
+	FocusOut // the widget has lost the keyboard focus
+
+	// Other mouse button events. MouseDown, MouseUp and Click are for the
+	// primary (left) mouse button.
+	RightClick  // the right mouse button was clicked on the widget
+	MiddleClick // the middle mouse button was clicked on the widget
+
+	// Multi-click events, sent along with Click (see EventData.ClickCount).
+	DoubleClick
+
+	// Typed drag/drop events for drop sites (see Supervisor.DragStartPayload).
+	DragEnter // a drag has moved onto the drop site
+	DragOver  // a drag is moving over the drop site
+	DragLeave // a drag has left the drop site, or was cancelled over it
+)
+
+// MouseButton identifies a mouse button in EventData.
+type MouseButton int
+
+// Mouse buttons. The zero value means no button.
+const (
+	MouseButtonLeft   MouseButton = iota + 1 // event.State.Button1
+	MouseButtonRight                         // event.State.Button2
+	MouseButtonMiddle                        // event.State.Button3
+)
+
+// Multi-click detection: clicks of the primary mouse button count as a double
+// (or triple...) click if they come within DoubleClickInterval of each other
+// and the cursor moved no more than DoubleClickSlop pixels.
+var (
+	DoubleClickInterval = 500 * time.Millisecond
+	DoubleClickSlop     = 4
 )
 
 // EventData carries common data to event handlers.
@@ -62,6 +99,26 @@ type EventData struct {
 	// a MouseMove
 	Clicked bool
 
+	// Button is the mouse button of MouseDown, MouseUp and click events.
+	Button MouseButton
+
+	// Propagating events: the deepest widget under the cursor that the event
+	// was dispatched to, and the current phase (see Supervisor.Dispatch).
+	// The Widget is the one whose handler is being called.
+	Target      Widget
+	Phase       EventPhase
+	propagation *propagation
+
+	// Typed drag/drop values: the payload on the drag events of drop sites
+	// and on Drop, and how the drag ended on the source's DragStop event.
+	Drag       *DragPayload
+	DropResult *DropResult
+	drop       *dropState
+
+	// ClickCount is 1 for a single Click, 2 for a double click, 3 for a
+	// triple click and so on.
+	ClickCount int
+
 	// A Value given e.g. from a ListBox click.
 	Value interface{}
 
@@ -78,6 +135,22 @@ type EventData struct {
 	// less than what fits in the list so the user sees some overlap as
 	// they scroll quickly by pages.
 	ScrollPages int // TODO: not implemented
+
+	// Mouse wheel movement on a Scroll event sent by the mouse wheel. A
+	// positive WheelY is scrolling up (away from the user) and a positive
+	// WheelX is scrolling right. Both are zero on other Scroll events.
+	WheelX int
+	WheelY int
+
+	// Keyboard event values.
+	Key    string // name of the key, e.g. "a", "Enter" or "Tab"
+	Rune   rune   // the character typed, or zero for non-printable keys
+	Repeat bool   // KeyPress was auto-repeated from a held down key
+
+	// Modifier keys held during a keyboard event.
+	Shift bool
+	Ctrl  bool
+	Alt   bool
 }
 
 // RelativePoint returns the ed.Point adjusted to be relative to the widget on screen.
@@ -93,13 +166,32 @@ func (ed EventData) RelativePoint() render.Point {
 // interaction events such as mouse hovers and clicks in their general
 // vicinity.
 type Supervisor struct {
+	// lock guards the widgets and their hover, click and keyboard focus
+	// state, the modals, DrawOnTop widgets and shortcuts, which may be read
+	// from any goroutine. It is never held while calling event handlers. The
+	// rest of the state is only touched on the UI goroutine, by Loop and the
+	// methods that send events.
 	lock     sync.RWMutex
 	serial   int                 // ID number of each widget added in order
-	widgets  map[int]WidgetSlot  // map of widget ID to WidgetSlot
+	widgets  []WidgetSlot        // widgets in the order they were added
+	ids      map[Widget]int      // map of widget to its ID number
+	hitIndex *hitIndex           // spatial index of the widgets' rects
 	hovering map[int]interface{} // map of widgets under the cursor
 	clicked  map[int]bool        // map of widgets being clicked
 	dd       *DragDrop
 
+	// Propagating mouse events.
+	hoverTarget    Widget                 // deepest widget under the cursor
+	downTarget     map[MouseButton]Widget // hoverTarget when each button was pressed
+	focusPrevented bool                   // MouseDown's PreventDefault was called
+	clicks         []MouseButton          // right and middle clicks on this tick
+
+	// Multi-click detection.
+	button1    bool         // primary mouse button was down on the last tick
+	lastClick  time.Time    // time of the last click
+	lastClickP render.Point // cursor position of the last click
+	clickCount int          // number of clicks in a row
+
 	// Stack of modal widgets that have event priority.
 	modals []Widget
 
@@ -109,6 +201,36 @@ type Supervisor struct {
 
 	// Widgets that we should draw on top, such as Tooltips.
 	onTop []Widget
+
+	// Keyboard focus.
+	focused    Widget   // widget holding the keyboard focus
+	modalFocus []Widget // focused widget from before each modal was pushed
+
+	// Keys held down on the last tick, for KeyUp and KeyPress repeats.
+	keysDown map[string]*keyState
+
+	// Registered keyboard shortcuts.
+	shortcuts []*Shortcut
+
+	// Queue of functions to run on the UI goroutine, see Invoke.
+	taskLock sync.Mutex
+	tasks    []func()
+	wake     chan struct{} // ends a wait for the next frame, see wait
+
+	// Clock, timers and tweens.
+	clock  func() time.Time // see SetClock
+	tick   time.Time        // time at the start of the current Loop
+	timers []*Timer
+	tweens []*Tween
+
+	// Functions called at the start of each Loop, see OnLoop.
+	loopHooks []func(*event.State)
+
+	// Changes of the windows, modals and DrawOnTop widgets, and their count
+	// when the last frame was drawn, see NeedsRedraw.
+	changes uint64 // atomic
+	drawn   bool
+	drawnAt uint64
 }
 
 // WidgetSlot holds a widget with a unique ID number in a sorted list.
@@ -117,15 +239,26 @@ type WidgetSlot struct {
 	widget Widget
 }
 
+// Widget returns the supervised widget.
+func (ws WidgetSlot) Widget() Widget {
+	return ws.widget
+}
+
 // NewSupervisor creates a supervisor.
 func NewSupervisor() *Supervisor {
 	return &Supervisor{
-		widgets:  map[int]WidgetSlot{},
-		hovering: map[int]interface{}{},
-		clicked:  map[int]bool{},
-		modals:   []Widget{},
-		onTop:    []Widget{},
-		dd:       NewDragDrop(),
+		widgets:    []WidgetSlot{},
+		ids:        map[Widget]int{},
+		hitIndex:   &hitIndex{},
+		hovering:   map[int]interface{}{},
+		clicked:    map[int]bool{},
+		modals:     []Widget{},
+		modalFocus: []Widget{},
+		onTop:      []Widget{},
+		keysDown:   map[string]*keyState{},
+		dd:         NewDragDrop(),
+		downTarget: map[MouseButton]Widget{},
+		wake:       make(chan struct{}, 1),
 	}
 }
 
@@ -142,17 +275,54 @@ func (s *Supervisor) DragStart() {
 //
 // The widget being dragged is given DragMove events while the drag is
 // underway. When the mouse button is released, the widget is given a
-// DragStop event and the widget below the cursor is given a Drop event.
+// DragStop event and the widgets below the cursor are given a Drop event.
 func (s *Supervisor) DragStartWidget(w Widget) {
 	s.dd.SetWidget(w)
 	s.dd.Start()
 }
 
-// DragStop stops the drag state.
+// DragStartPayload starts a typed drag of a payload from a source widget,
+// such as a palette swatch being dragged to a ListBox.
+//
+// Drop sites are widgets with a handler for DragEnter, DragOver or Drop. The
+// deepest one under the cursor is sent DragEnter when the drag moves onto it,
+// DragOver on every tick while it stays there and DragLeave when it moves
+// away; a handler calls EventData.AcceptDrop to accept the payload (the
+// DragOver handler, if the site has one, has the final say). When the mouse
+// button is released over a drop site that accepts, it is sent a Drop event.
+//
+// The source is sent DragMove events during the drag and a DragStop event
+// with the DropResult at the end. The optional ghost widget (e.g. an Image
+// of the thing being dragged) is drawn on top, following the cursor.
+func (s *Supervisor) DragStartPayload(source Widget, payload DragPayload, ghost Widget) {
+	if s.dd.IsDragging() {
+		s.DragStop()
+	}
+
+	s.dd.SetWidget(source)
+	s.dd.SetPayload(payload)
+	if ghost != nil {
+		s.dd.SetGhost(ghost)
+		s.DrawOnTop(ghost)
+	}
+	s.dd.Start()
+}
+
+// DragStop stops the drag state. A typed drag is cancelled: no Drop event is
+// sent, and the source is told that the drop was not accepted.
 func (s *Supervisor) DragStop() {
+	if s.dd.IsDragging() && s.dd.Payload() != nil {
+		s.finishDrag(false)
+		return
+	}
 	s.dd.Stop()
 }
 
+// DragDrop returns the Supervisor's drag/drop state.
+func (s *Supervisor) DragDrop() *DragDrop {
+	return s.dd
+}
+
 // IsDragging returns whether the drag state is enabled.
 func (s *Supervisor) IsDragging() bool {
 	return s.dd.IsDragging()
@@ -178,33 +348,19 @@ func (s *Supervisor) Loop(ev *event.State) error {
 		}
 	)
 
-	// See if we are hovering over any widgets.
-	hovering, outside := s.Hovering(XY)
-
-	// If we are dragging something around, do not trigger any mouse events
-	// to other widgets but DO notify any widget we dropped on top of!
-	if s.dd.IsDragging() {
-		if !ev.Button1 && !ev.Button3 {
-			// The mouse has been released. TODO: make mouse button important?
-			for _, child := range hovering {
-				child.widget.Event(Drop, EventData{
-					Widget: child.widget,
-					Point:  XY,
-				})
-			}
-			s.DragStop()
-		} else {
-			// If we have a target widget being dragged, send it mouse events.
-			if target := s.dd.Widget(); target != nil {
-				target.Event(DragMove, EventData{
-					Widget: target,
-					Point:  XY,
-				})
-			}
-		}
-		return ErrStopPropagation
+	s.tick = s.Now()
+	for _, fn := range s.loopHooks {
+		fn(ev)
 	}
 
+	// Run the functions queued by other goroutines, timers and tweens.
+	s.runTasks()
+	s.runTimers(s.tick)
+	s.runTweens(s.tick)
+
+	// See if we are hovering over any widgets.
+	hovering, outside := s.hitWidgets(XY)
+
 	// Check if the top focused window has been closed and auto-focus the next.
 	if s.winFocus != nil && s.winFocus.window.Hidden() {
 		next := s.winFocus.next
@@ -217,11 +373,30 @@ func (s *Supervisor) Loop(ev *event.State) error {
 		}
 	}
 
+	// Send keyboard events to the focused widget.
+	s.loopFocus()
+	s.loopKeys(ev)
+
+	// Count multiple clicks in a row.
+	s.loopClicks(ev, XY)
+
+	// If we are dragging something around, do not trigger any mouse events
+	// to other widgets but DO notify any widget we dropped on top of!
+	if s.dd.IsDragging() {
+		s.loopDrag(ev, XY, hovering)
+		return ErrStopPropagation
+	}
+
+	// Dispatch mouse events through the parent chain of the widget under
+	// the cursor, and the mouse wheel.
+	s.loopPropagation(ev, XY, hovering)
+	s.loopWheel(ev, XY, hovering)
+
 	// Run events in managed windows first, from top to bottom.
 	// Widgets in unmanaged windows will be handled next.
 	// err := s.runWindowEvents(XY, ev, hovering, outside)
 	// Only run if there is no active modal (modals have top priority)
-	if len(s.modals) == 0 {
+	if s.GetModal() == nil {
 		handled, err := s.runWidgetEvents(XY, ev, hovering, outside, true)
 		if err == ErrStopPropagation || handled {
 			// A widget in the active window has accepted an event. Do not pass
@@ -237,29 +412,48 @@ func (s *Supervisor) Loop(ev *event.State) error {
 	return nil
 }
 
+// loopClicks is a subroutine of Supervisor.Loop that counts clicks of the
+// primary mouse button for the ClickCount of Click events. The count goes up
+// when the button is released close in time and space to the previous click,
+// and resets to 1 otherwise.
+func (s *Supervisor) loopClicks(ev *event.State, XY render.Point) {
+	if !ev.Button1 && s.button1 {
+		var (
+			now   = s.tick
+			delta = s.lastClickP.Compare(XY)
+		)
+
+		if s.clickCount > 0 && now.Sub(s.lastClick) <= DoubleClickInterval &&
+			absInt(delta.X) <= DoubleClickSlop && absInt(delta.Y) <= DoubleClickSlop {
+			s.clickCount++
+		} else {
+			s.clickCount = 1
+		}
+
+		s.lastClick = now
+		s.lastClickP = XY
+	}
+	s.button1 = ev.Button1
+}
+
 // Hovering returns all of the widgets managed by Supervisor that are under
 // the mouse cursor. Returns the set of widgets below the cursor and the set
 // of widgets not below the cursor.
 func (s *Supervisor) Hovering(cursor render.Point) (hovering, outside []WidgetSlot) {
-	var XY = cursor // for shorthand
 	hovering = []WidgetSlot{}
 	outside = []WidgetSlot{}
 
-	// Check all the widgets under our care.
-	for child := range s.Widgets() {
-		var (
-			w  = child.widget
-			P  = AbsolutePosition(w)
-			S  = w.Size()
-			P2 = render.Point{
-				X: P.X + S.W,
-				Y: P.Y + S.H,
-			}
-		)
+	s.lock.RLock()
+	defer s.lock.RUnlock()
 
-		if XY.X >= P.X && XY.X < P2.X && XY.Y >= P.Y && XY.Y < P2.Y {
-			// Cursor intersects the widget.
+	var (
+		hits = s.queryHits(cursor)
+		next int // next index of hits
+	)
+	for i, child := range s.widgets {
+		if next < len(hits) && hits[next] == i {
 			hovering = append(hovering, child)
+			next++
 		} else {
 			outside = append(outside, child)
 		}
@@ -268,6 +462,95 @@ func (s *Supervisor) Hovering(cursor render.Point) (hovering, outside []WidgetSl
 	return hovering, outside
 }
 
+// hitWidgets is the version of Hovering used by Loop. The outside widgets
+// are only those the Supervisor has hover or click state for, which are the
+// only ones that need MouseOut or MouseUp events.
+func (s *Supervisor) hitWidgets(cursor render.Point) (hovering, outside []WidgetSlot) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	var (
+		hits = s.queryHits(cursor)
+		hit  = map[int]interface{}{}
+	)
+
+	hovering = make([]WidgetSlot, 0, len(hits))
+	for _, i := range hits {
+		hovering = append(hovering, s.widgets[i])
+		hit[s.widgets[i].id] = nil
+	}
+
+	// Widgets with state that are no longer under the cursor.
+	var ids = map[int]interface{}{}
+	for id := range s.hovering {
+		ids[id] = nil
+	}
+	for id := range s.clicked {
+		ids[id] = nil
+	}
+	for id := range ids {
+		if _, ok := hit[id]; ok {
+			continue
+		}
+		if slot, ok := s.widgetByID(id); ok {
+			outside = append(outside, slot)
+		}
+	}
+	sort.Slice(outside, func(i, j int) bool {
+		return outside[i].id < outside[j].id
+	})
+
+	return hovering, outside
+}
+
+// slots returns a copy of the supervised widgets list, for callers that
+// should not hold the lock while they work with it.
+func (s *Supervisor) slots() []WidgetSlot {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return append([]WidgetSlot{}, s.widgets...)
+}
+
+// widgetByID finds a supervised widget by its ID number. The caller must
+// hold the lock.
+func (s *Supervisor) widgetByID(id int) (WidgetSlot, bool) {
+	i := sort.Search(len(s.widgets), func(i int) bool {
+		return s.widgets[i].id >= id
+	})
+	if i < len(s.widgets) && s.widgets[i].id == id {
+		return s.widgets[i], true
+	}
+	return WidgetSlot{}, false
+}
+
+// swapHovering sets whether the cursor is over a widget, and returns whether
+// it was before.
+func (s *Supervisor) swapHovering(id int, v bool) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	_, was := s.hovering[id]
+	if v {
+		s.hovering[id] = nil
+	} else {
+		delete(s.hovering, id)
+	}
+	return was
+}
+
+// swapClicked sets whether the primary button is held down on a widget, and
+// returns whether it was before.
+func (s *Supervisor) swapClicked(id int, v bool) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	was := s.clicked[id]
+	if v {
+		s.clicked[id] = true
+	} else {
+		delete(s.clicked, id)
+	}
+	return was
+}
+
 // runWindowEvents is a subroutine of Supervisor.Loop().
 //
 // After determining the widgets below the cursor (hovering) and outside the
@@ -300,10 +583,7 @@ func (s *Supervisor) runWidgetEvents(XY render.Point, ev *event.State,
 
 	// Do we have active modals? Modal widgets have top event priority given
 	// only to the top-most modal.
-	var modal Widget
-	if len(s.modals) > 0 {
-		modal = s.modals[len(s.modals)-1]
-	}
+	var modal = s.GetModal()
 
 	// If we're running this method in "Phase 2" (to widgets NOT in the focused
 	// window), only send mouse events to widgets if the cursor is NOT inside
@@ -386,34 +666,58 @@ func (s *Supervisor) runWidgetEvents(XY render.Point, ev *event.State,
 		}
 
 		// Cursor has intersected the widget.
-		if _, ok := s.hovering[id]; !ok {
+		if !s.swapHovering(id, true) {
 			handle(w.Event(MouseOver, EventData{
 				Widget: w,
 				Point:  XY,
 			}))
-			s.hovering[id] = nil
 		}
 
-		isClicked := s.clicked[id]
 		if ev.Button1 {
-			if !isClicked {
+			if !s.swapClicked(id, true) {
 				err := w.Event(MouseDown, EventData{
 					Widget: w,
 					Point:  XY,
+					Button: MouseButtonLeft,
 				})
 				handle(err)
-				s.clicked[id] = true
+
+				// Clicking a focusable widget gives it the keyboard focus.
+				if isFocusable(w) && !s.focusPrevented {
+					s.Focus(w)
+				}
 			}
-		} else if isClicked {
+		} else if s.swapClicked(id, false) {
 			handle(w.Event(MouseUp, EventData{
 				Widget: w,
 				Point:  XY,
+				Button: MouseButtonLeft,
 			}))
 			handle(w.Event(Click, EventData{
-				Widget: w,
-				Point:  XY,
+				Widget:     w,
+				Point:      XY,
+				Button:     MouseButtonLeft,
+				ClickCount: s.clickCount,
+			}))
+			if s.clickCount == 2 {
+				handle(w.Event(DoubleClick, EventData{
+					Widget:     w,
+					Point:      XY,
+					Button:     MouseButtonLeft,
+					ClickCount: s.clickCount,
+				}))
+			}
+		}
+
+		// Right and middle clicks, when loopPropagation saw the button
+		// pressed and released over the same widget.
+		for _, button := range s.clicks {
+			handle(w.Event(clickEvents[button], EventData{
+				Supervisor: s,
+				Widget:     w,
+				Point:      XY,
+				Button:     button,
 			}))
-			delete(s.clicked, id)
 		}
 
 		// Mouse movement. NOTE: it is intentional that this fires on
@@ -440,27 +744,26 @@ func (s *Supervisor) runWidgetEvents(XY render.Point, ev *event.State,
 		}
 
 		// Cursor is not intersecting the widget.
-		if _, ok := s.hovering[id]; ok {
+		if s.swapHovering(id, false) {
 			handle(w.Event(MouseOut, EventData{
 				Widget: w,
 				Point:  XY,
 			}))
-			delete(s.hovering, id)
 		}
 
-		if _, ok := s.clicked[id]; ok {
+		if s.swapClicked(id, false) {
 			handle(w.Event(MouseUp, EventData{
 				Widget: w,
 				Point:  XY,
+				Button: MouseButtonLeft,
 			}))
-			delete(s.clicked, id)
 		}
 	}
 
 	// If a modal is active and a click was registered outside the modal's
 	// bounding box, send the CloseModal event.
 	if modal != nil && !XY.Inside(AbsoluteRect(modal)) {
-		if ev.Button1 {
+		if ev.Button1 || ev.Button2 || ev.Button3 {
 			modal.Event(CloseModal, EventData{
 				Supervisor: s,
 			})
@@ -483,20 +786,32 @@ func (s *Supervisor) runWidgetEvents(XY render.Point, ev *event.State,
 }
 
 // Widgets returns a channel of widgets managed by the supervisor in the order
-// they were added.
+// they were added. The channel is buffered with all of the widgets and closed.
 func (s *Supervisor) Widgets() <-chan WidgetSlot {
-	pipe := make(chan WidgetSlot)
-	go func() {
-		for i := 0; i < s.serial; i++ {
-			if w, ok := s.widgets[i]; ok {
-				pipe <- w
-			}
-		}
-		close(pipe)
-	}()
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	pipe := make(chan WidgetSlot, len(s.widgets))
+	for _, w := range s.widgets {
+		pipe <- w
+	}
+	close(pipe)
 	return pipe
 }
 
+// Compute the modals managed by the supervisor, such as popup menus that
+// position themselves on screen. Call it each frame after computing your
+// own widgets and before Present.
+func (s *Supervisor) Compute(e render.Engine) {
+	s.lock.RLock()
+	var modals = append([]Widget{}, s.modals...)
+	s.lock.RUnlock()
+
+	for _, modal := range modals {
+		modal.Compute(e)
+	}
+}
+
 // Present all widgets managed by the supervisor.
 //
 // NOTE: only the Window Manager feature uses this method, and this method
@@ -505,22 +820,28 @@ func (s *Supervisor) Widgets() <-chan WidgetSlot {
 // Present on them each time the parent Presents, or otherwise you need to
 // manage the presentation of widgets outside the Supervisor.
 func (s *Supervisor) Present(e render.Engine) {
+	// Copy the lists of modals and widgets on top, so the lock isn't held
+	// while widgets draw themselves (and may call other Supervisor methods).
 	s.lock.RLock()
-	defer s.lock.RUnlock()
+	var (
+		modals = append([]Widget{}, s.modals...)
+		onTop  = append([]Widget{}, s.onTop...)
+	)
+	s.lock.RUnlock()
 
 	// Render the window manager windows from bottom to top.
 	s.presentWindows(e)
 
 	// Render the modals from bottom to top.
-	if len(s.modals) > 0 {
-		for _, modal := range s.modals {
+	if len(modals) > 0 {
+		for _, modal := range modals {
 			modal.Present(e, modal.Point())
 		}
 	}
 
 	// Render any "on top" widgets like Tooltips.
-	if len(s.onTop) > 0 {
-		for _, widget := range s.onTop {
+	if len(onTop) > 0 {
+		for _, widget := range onTop {
 			if widget.Hidden() {
 				continue
 			}
@@ -533,21 +854,21 @@ func (s *Supervisor) Present(e render.Engine) {
 // under the supervisor's care.
 func (s *Supervisor) Add(w Widget) {
 	s.lock.Lock()
+	defer s.lock.Unlock()
 
 	// Check it's not already there.
-	for _, child := range s.widgets {
-		if child.widget == w {
-			return
-		}
+	if _, ok := s.ids[w]; ok {
+		return
 	}
 
 	// Add it.
-	s.widgets[s.serial] = WidgetSlot{
+	s.widgets = append(s.widgets, WidgetSlot{
 		id:     s.serial,
 		widget: w,
-	}
+	})
+	s.ids[w] = s.serial
 	s.serial++
-	s.lock.Unlock()
+	s.hitIndex.dirty = true
 }
 
 // PushModal sets the widget to be a "modal" for the Supervisor.
@@ -560,9 +881,19 @@ func (s *Supervisor) Add(w Widget) {
 // If a Click event registers OUTSIDE the bounds of the modal widget, the
 // widget receives a CloseModal event.
 //
+// The keyboard focus is confined to the active modal: the currently focused
+// widget loses focus while the modal is up and gets it back when the modal
+// is popped.
+//
 // Returns the length of the modal stack.
 func (s *Supervisor) PushModal(w Widget) int {
+	var focused = s.blur()
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.modalFocus = append(s.modalFocus, focused)
 	s.modals = append(s.modals, w)
+	s.invalidate()
 	return len(s.modals)
 }
 
@@ -574,22 +905,41 @@ func (s *Supervisor) PushModal(w Widget) int {
 // newer modal on the stack, this PopModal action would do nothing.
 func (s *Supervisor) PopModal(w Widget) bool {
 	// only can pop if the topmost widget is the one being asked for
-	if len(s.modals) > 0 && s.modals[len(s.modals)-1] == w {
-		modal := s.modals[len(s.modals)-1]
-		modal.Hide()
+	s.lock.Lock()
+	if len(s.modals) == 0 || s.modals[len(s.modals)-1] != w {
+		s.lock.Unlock()
+		return false
+	}
+
+	// pop it off
+	modal := s.modals[len(s.modals)-1]
+	s.modals = s.modals[:len(s.modals)-1]
+	s.invalidate()
+
+	var prev Widget
+	if n := len(s.modalFocus); n > 0 {
+		prev = s.modalFocus[n-1]
+		s.modalFocus = s.modalFocus[:n-1]
+	}
+	s.lock.Unlock()
 
-		// pop it off
-		s.modals = s.modals[:len(s.modals)-1]
+	modal.Hide()
 
-		return true
+	// Restore the keyboard focus from before the modal was pushed.
+	s.Blur()
+	if prev != nil {
+		s.Focus(prev)
 	}
 
-	return false
+	return true
 }
 
 // GetModal returns the modal on the top of the stack, or nil if there is
 // no modal on top.
 func (s *Supervisor) GetModal() Widget {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
 	if len(s.modals) == 0 {
 		return nil
 	}
@@ -614,5 +964,21 @@ The current draw order of the Supervisor is as follows:
     not to be overwritten by neighboring widgets.
 */
 func (s *Supervisor) DrawOnTop(w Widget) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
 	s.onTop = append(s.onTop, w)
+	s.invalidate()
+}
+
+// removeOnTop takes a widget out of the DrawOnTop list.
+func (s *Supervisor) removeOnTop(w Widget) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for i, other := range s.onTop {
+		if other == w {
+			s.onTop = append(s.onTop[:i], s.onTop[i+1:]...)
+			s.invalidate()
+			return
+		}
+	}
 }
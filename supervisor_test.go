@@ -0,0 +1,171 @@
+package ui
+
+import (
+	"testing"
+
+	"git.kirsle.net/go/render"
+	"git.kirsle.net/go/render/event"
+)
+
+func TestMouseButtons(t *testing.T) {
+	var (
+		s      = NewSupervisor()
+		btn    = NewButton("Button", NewLabel(Label{Text: "Hello"}))
+		counts = []int{}
+		events = []Event{}
+	)
+	btn.Resize(render.NewRect(100, 20))
+	s.Add(btn)
+
+	btn.Handle(Click, func(ed EventData) error {
+		counts = append(counts, ed.ClickCount)
+		events = append(events, Click)
+		return nil
+	})
+	for _, e := range []Event{DoubleClick, RightClick, MiddleClick} {
+		e := e
+		btn.Handle(e, func(ed EventData) error {
+			events = append(events, e)
+			return nil
+		})
+	}
+
+	ev := event.NewState()
+	ev.CursorX, ev.CursorY = 10, 10
+	click := func(button *bool) {
+		*button = true
+		s.Loop(ev)
+		*button = false
+		s.Loop(ev)
+	}
+
+	click(&ev.Button1)
+	click(&ev.Button1)
+	click(&ev.Button1)
+	click(&ev.Button2)
+	click(&ev.Button3)
+
+	expectCounts := []int{1, 2, 3}
+	expectEvents := []Event{Click, Click, DoubleClick, Click, RightClick, MiddleClick}
+	if len(counts) != len(expectCounts) || len(events) != len(expectEvents) {
+		t.Fatalf("expected counts %v and events %v, got %v and %v",
+			expectCounts, expectEvents, counts, events)
+	}
+	for i := range expectCounts {
+		if counts[i] != expectCounts[i] {
+			t.Errorf("click %d: expected ClickCount %d, got %d", i, expectCounts[i], counts[i])
+		}
+	}
+	for i := range expectEvents {
+		if events[i] != expectEvents[i] {
+			t.Errorf("event %d: expected %d, got %d", i, expectEvents[i], events[i])
+		}
+	}
+
+	// Clicks further apart than the slop start over.
+	counts = counts[:0]
+	ev.CursorX += DoubleClickSlop + 1
+	click(&ev.Button1)
+	if len(counts) != 1 || counts[0] != 1 {
+		t.Errorf("expected a single click after moving the cursor, got %v", counts)
+	}
+}
+
+func TestMouseWheel(t *testing.T) {
+	var (
+		s      = NewSupervisor()
+		frame  = NewFrame("Parent")
+		btn    = NewButton("Button", NewLabel(Label{Text: "Hello"}))
+		target Widget
+		wheel  int
+	)
+	frame.Resize(render.NewRect(200, 100))
+	btn.Resize(render.NewRect(100, 20))
+	frame.Pack(btn)
+	s.Add(frame)
+	s.Add(btn)
+
+	// The button has no Scroll handler: the event bubbles up to the frame.
+	frame.HandleBubble(Scroll, func(ed EventData) error {
+		target = ed.Target
+		wheel = ed.WheelY
+		return ErrStopPropagation
+	})
+
+	ev := event.NewState()
+	ev.CursorX, ev.CursorY = 10, 10
+	ev.WheelY = -2
+	s.Loop(ev)
+
+	if target != btn || wheel != -2 {
+		t.Errorf("expected the frame to get WheelY -2 from the button, got %v and %d", target, wheel)
+	}
+}
+
+func TestPropagation(t *testing.T) {
+	var (
+		s     = NewSupervisor()
+		frame = NewFrame("Parent")
+		btn   = NewButton("Button", NewLabel(Label{Text: "Hello"}))
+		log   = []string{}
+		stop  bool
+	)
+	frame.Resize(render.NewRect(200, 100))
+	btn.Resize(render.NewRect(100, 20))
+	frame.Pack(btn)
+
+	// Only the frame is supervised: the button gets its events by dispatch.
+	s.Add(frame)
+
+	frame.HandleCapture(Click, func(ed EventData) error {
+		log = append(log, "capture")
+		if stop {
+			ed.StopPropagation()
+		}
+		return nil
+	})
+	btn.Handle(Click, func(ed EventData) error {
+		log = append(log, "target")
+		return nil
+	})
+	frame.HandleBubble(Click, func(ed EventData) error {
+		if ed.Target == btn && ed.Phase == BubblePhase {
+			log = append(log, "bubble")
+		}
+		return nil
+	})
+	frame.HandleCapture(MouseDown, func(ed EventData) error {
+		ed.PreventDefault()
+		return nil
+	})
+
+	ev := event.NewState()
+	ev.CursorX, ev.CursorY = 10, 10
+	click := func() {
+		ev.Button1 = true
+		s.Loop(ev)
+		ev.Button1 = false
+		s.Loop(ev)
+	}
+
+	click()
+	stop = true
+	click()
+
+	expect := []string{"capture", "target", "bubble", "capture"}
+	if len(log) != len(expect) {
+		t.Fatalf("expected %v, got %v", expect, log)
+	}
+	for i := range expect {
+		if log[i] != expect[i] {
+			t.Errorf("step %d: expected %s, got %s", i, expect[i], log[i])
+		}
+	}
+
+	// PreventDefault on MouseDown keeps the button from taking the focus.
+	s.Add(btn)
+	click()
+	if s.FocusedWidget() != nil {
+		t.Errorf("expected no focused widget, got %s", s.FocusedWidget())
+	}
+}
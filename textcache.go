@@ -0,0 +1,330 @@
+package ui
+
+import (
+	"container/list"
+	"fmt"
+	"image"
+	"reflect"
+	"sync"
+
+	"git.kirsle.net/go/render"
+)
+
+// DefaultTextCache is the cache used by Labels and Tooltips to measure and
+// draw their lines of text. Set it to nil to disable caching.
+var DefaultTextCache = NewTextCache()
+
+// Default limits of a new TextCache.
+var (
+	DefaultTextCacheRects        = 4096             // measured lines of text
+	DefaultTextCacheTextureBytes = 16 * 1024 * 1024 // 16 MiB of RGBA textures
+)
+
+// TextRenderer draws a line of text onto a new image, with a transparent
+// background. The text's top left corner is the point (0,0) of the image,
+// whose Bounds may start at negative coordinates for a stroke or shadow
+// that reaches above or to the left of it.
+type TextRenderer func(render.Text) (image.Image, error)
+
+// TextCache caches the sizes of lines of text measured by the engine, and
+// optionally pre-rendered textures of them, so that a line of text is only
+// measured and rendered once while it is in use. Lines are cached by their
+// text and all their font properties: font file, size, color, stroke, shadow
+// and so on, so a Label whose TextVariable changed looks up its new text
+// and the old one is evicted when it goes unused. Both caches are bounded
+// and evict the least recently used lines first.
+//
+// The measurements are shared by the engines of the same type. Textures are
+// made by the cache's Renderer, or by default by the engine itself if it can
+// render text to images, like the softrender Engine. Otherwise caching text
+// as textures is opt-in, and the text is drawn with the engine's DrawText
+// every frame. For example, to cache the text of an SDL2 program as textures
+// rendered by the softrender package:
+//
+//	ui.DefaultTextCache.Renderer = softrender.New(0, 0).RenderText
+//
+// The textures belong to their engine: Clear the cache when an engine is
+// torn down.
+type TextCache struct {
+	MaxRects        int          // number of measured lines to keep, if any
+	MaxTextureBytes int          // memory for textures, at 4 bytes a pixel
+	Renderer        TextRenderer // renders the textures, see above
+
+	lock         sync.Mutex
+	rects        *lruCache
+	textures     *lruCache
+	textureBytes int
+	serial       int
+	stats        TextCacheStats
+}
+
+// TextCacheStats counts the lookups of a TextCache, to tune its limits.
+type TextCacheStats struct {
+	RectHits      int // lines measured from the cache
+	RectMisses    int // lines measured by the engine
+	TextureHits   int // lines drawn from a cached texture
+	TextureMisses int // lines rendered to a new texture
+	Evictions     int // lines or textures evicted by the limits
+
+	Rects        int // lines measured in the cache now
+	Textures     int // textures in the cache now
+	TextureBytes int // memory used by the textures now
+}
+
+// textKey identifies a line of text measured by a type of engine, or drawn
+// by an engine.
+type textKey struct {
+	engine interface{} // the engine's reflect.Type, or the engine
+	text   render.Text
+}
+
+// cachedTexture is a line of text stored as a texture. The origin is the
+// point in the texture where the text's top left corner is.
+type cachedTexture struct {
+	texture render.Texturer
+	origin  render.Point
+	bytes   int
+}
+
+// NewTextCache creates a text cache with the default limits.
+func NewTextCache() *TextCache {
+	return &TextCache{
+		MaxRects:        DefaultTextCacheRects,
+		MaxTextureBytes: DefaultTextCacheTextureBytes,
+		rects:           newLRUCache(),
+		textures:        newLRUCache(),
+	}
+}
+
+// ComputeTextRect returns the size of a line of text, from the cache or else
+// measured by the engine. A nil cache always asks the engine.
+func (c *TextCache) ComputeTextRect(e render.Engine, text render.Text) (render.Rect, error) {
+	if c == nil {
+		return e.ComputeTextRect(text)
+	}
+
+	key := textKey{reflect.TypeOf(unwrapEngine(e)), text}
+	c.lock.Lock()
+	if rect, ok := c.rects.get(key); ok {
+		c.stats.RectHits++
+		c.lock.Unlock()
+		return rect.(render.Rect), nil
+	}
+	c.stats.RectMisses++
+	c.lock.Unlock()
+
+	rect, err := e.ComputeTextRect(text)
+	if err != nil {
+		return rect, err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.rects.put(key, rect)
+	for c.rects.len() > c.MaxRects {
+		if c.rects.evict() == nil {
+			break
+		}
+		c.stats.Evictions++
+	}
+	return rect, nil
+}
+
+// DrawText draws a line of text from its cached texture, rendering it first
+// if needed. Text that can't be cached as a texture is drawn by the engine.
+func (c *TextCache) DrawText(e render.Engine, text render.Text, p render.Point) error {
+	if c == nil || text.Text == "" {
+		return e.DrawText(text, p)
+	}
+
+	var renderer = c.renderer(e)
+	if renderer == nil {
+		return e.DrawText(text, p)
+	}
+	return c.drawText(e, renderer, text, p)
+}
+
+// renderer returns the cache's Renderer, or else the engine's own if it can
+// render text to images. Returns nil if neither can.
+func (c *TextCache) renderer(e render.Engine) TextRenderer {
+	if c == nil {
+		return nil
+	} else if c.Renderer != nil {
+		return c.Renderer
+	} else if tr, ok := unwrapEngine(e).(textRendererEngine); ok {
+		return tr.RenderText
+	}
+	return nil
+}
+
+// drawText draws a line of text from a texture made by the renderer. The
+// textures belong to the engine under a ClipEngine, which draws them
+// clipped.
+func (c *TextCache) drawText(e render.Engine, renderer TextRenderer, text render.Text, p render.Point) error {
+	key := textKey{unwrapEngine(e), text}
+	c.lock.Lock()
+	cached, ok := c.textures.get(key)
+	if ok {
+		c.stats.TextureHits++
+	}
+	c.lock.Unlock()
+
+	if !ok {
+		tex, err := c.render(unwrapEngine(e), renderer, text)
+		if err != nil || tex == nil {
+			return unwrapEngine(e).DrawText(text, p)
+		}
+		cached = tex
+	}
+
+	tex := cached.(*cachedTexture)
+	size := tex.texture.Size()
+	e.Copy(tex.texture, size, render.Rect{
+		X: p.X - tex.origin.X,
+		Y: p.Y - tex.origin.Y,
+		W: size.W,
+		H: size.H,
+	})
+	return nil
+}
+
+// render stores a new texture of a line of text, or returns nil if the
+// texture is too large for the cache.
+func (c *TextCache) render(e render.Engine, renderer TextRenderer, text render.Text) (*cachedTexture, error) {
+	img, err := renderer(text)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		bounds = img.Bounds()
+		bytes  = bounds.Dx() * bounds.Dy() * 4
+	)
+	if bytes == 0 || bytes > c.MaxTextureBytes {
+		return nil, nil
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	// Rendered meanwhile on another goroutine?
+	if cached, ok := c.textures.get(textKey{e, text}); ok {
+		return cached.(*cachedTexture), nil
+	}
+
+	c.serial++
+	texture, err := e.StoreTexture(fmt.Sprintf("ui.TextCache#%d", c.serial), img)
+	if err != nil {
+		return nil, err
+	}
+
+	tex := &cachedTexture{
+		texture: texture,
+		origin:  render.NewPoint(-bounds.Min.X, -bounds.Min.Y),
+		bytes:   bytes,
+	}
+	c.stats.TextureMisses++
+	c.textures.put(textKey{e, text}, tex)
+	c.textureBytes += bytes
+	for c.textureBytes > c.MaxTextureBytes {
+		evicted := c.textures.evict()
+		if evicted == nil {
+			break
+		}
+		c.freeTexture(evicted)
+		c.stats.Evictions++
+	}
+	return tex, nil
+}
+
+// freeTexture frees an evicted texture from its engine.
+func (c *TextCache) freeTexture(v interface{}) {
+	tex := v.(*cachedTexture)
+	c.textureBytes -= tex.bytes
+	tex.texture.Free()
+}
+
+// Stats returns the counters of the cache.
+func (c *TextCache) Stats() TextCacheStats {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	stats := c.stats
+	stats.Rects = c.rects.len()
+	stats.Textures = c.textures.len()
+	stats.TextureBytes = c.textureBytes
+	return stats
+}
+
+// ResetStats sets the hit, miss and eviction counters back to zero.
+func (c *TextCache) ResetStats() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.stats = TextCacheStats{}
+}
+
+// Clear empties the cache and frees its textures, e.g. before the engine
+// is torn down or after it freed all its textures.
+func (c *TextCache) Clear() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for c.rects.len() > 0 {
+		c.rects.evict()
+	}
+	for c.textures.len() > 0 {
+		c.freeTexture(c.textures.evict())
+	}
+}
+
+// lruCache is a map that remembers the order its keys were used in.
+type lruCache struct {
+	order *list.List // of *lruEntry, most recently used first
+	items map[textKey]*list.Element
+}
+
+type lruEntry struct {
+	key   textKey
+	value interface{}
+}
+
+func newLRUCache() *lruCache {
+	return &lruCache{
+		order: list.New(),
+		items: map[textKey]*list.Element{},
+	}
+}
+
+// get a value and mark it most recently used.
+func (c *lruCache) get(key textKey) (interface{}, bool) {
+	if el, ok := c.items[key]; ok {
+		c.order.MoveToFront(el)
+		return el.Value.(*lruEntry).value, true
+	}
+	return nil, false
+}
+
+// put a value as the most recently used.
+func (c *lruCache) put(key textKey, value interface{}) {
+	if el, ok := c.items[key]; ok {
+		el.Value.(*lruEntry).value = value
+		c.order.MoveToFront(el)
+		return
+	}
+	c.items[key] = c.order.PushFront(&lruEntry{key, value})
+}
+
+// evict removes the least recently used value and returns it.
+func (c *lruCache) evict() interface{} {
+	el := c.order.Back()
+	if el == nil {
+		return nil
+	}
+	entry := c.order.Remove(el).(*lruEntry)
+	delete(c.items, entry.key)
+	return entry.value
+}
+
+func (c *lruCache) len() int {
+	return c.order.Len()
+}
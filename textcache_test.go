@@ -0,0 +1,139 @@
+package ui_test
+
+import (
+	"bytes"
+	"testing"
+
+	"git.kirsle.net/go/render"
+	"git.kirsle.net/go/ui"
+	"git.kirsle.net/go/ui/softrender"
+)
+
+func TestTextCacheRects(t *testing.T) {
+	var (
+		cache  = ui.NewTextCache()
+		engine = &countingEngine{Engine: softrender.New(100, 100)}
+	)
+	cache.MaxRects = 2
+
+	measure := func(text string) render.Rect {
+		rect, err := cache.ComputeTextRect(engine, render.Text{Text: text, Size: 12})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return rect
+	}
+
+	// b is the least recently used when c comes in.
+	first := measure("a")
+	measure("b")
+	if measure("a") != first {
+		t.Errorf("expected the cached rect to match")
+	}
+	measure("c")
+	measure("b")
+
+	stats := cache.Stats()
+	if stats.RectHits != 1 || stats.RectMisses != 4 || stats.Evictions != 2 || stats.Rects != 2 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+	if engine.measured != 4 {
+		t.Errorf("expected 4 measurements by the engine, got %d", engine.measured)
+	}
+
+	// Another engine of the same type shares the measurements.
+	measure("b")
+	cache.ComputeTextRect(softrender.New(10, 10), render.Text{Text: "b", Size: 12})
+	if stats := cache.Stats(); stats.RectHits != 2 {
+		t.Errorf("expected only one more hit, got %+v", stats)
+	}
+
+	cache.ResetStats()
+	if stats := cache.Stats(); stats.RectMisses != 0 || stats.Rects != 2 {
+		t.Errorf("expected the counters to reset but not the cache: %+v", stats)
+	}
+}
+
+func TestTextCacheTextures(t *testing.T) {
+	var (
+		cache   = ui.NewTextCache()
+		cached  = softrender.New(100, 40)
+		direct  = softrender.New(100, 40)
+		text    = render.Text{Text: "Hello", Size: 12, Color: testForeground}
+		p       = render.NewPoint(10, 10)
+		outline = render.Text{Text: "Hello", Size: 12, Color: testForeground, Stroke: testHighlight}
+	)
+	cache.Renderer = cached.RenderText
+
+	// The same pixels as drawing directly.
+	for i := 0; i < 2; i++ {
+		cached.Clear(testBackground)
+		cache.DrawText(cached, text, p)
+	}
+	direct.Clear(testBackground)
+	direct.DrawText(text, p)
+	if !bytes.Equal(cached.Image().Pix, direct.Image().Pix) {
+		t.Errorf("expected the cached texture to look like the text")
+	}
+
+	stats := cache.Stats()
+	if stats.TextureMisses != 1 || stats.TextureHits != 1 || stats.Textures != 1 || stats.TextureBytes == 0 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+
+	// The memory limit evicts the oldest texture.
+	cache.MaxTextureBytes = stats.TextureBytes * 3 / 2
+	cache.DrawText(cached, outline, p)
+	if stats := cache.Stats(); stats.Textures != 1 || stats.Evictions != 1 {
+		t.Errorf("expected the first texture to be evicted: %+v", stats)
+	}
+
+	cache.Clear()
+	if stats := cache.Stats(); stats.Textures != 0 || stats.TextureBytes != 0 {
+		t.Errorf("expected an empty cache: %+v", stats)
+	}
+}
+
+// Without a Renderer the textures are rendered by an engine that can, and
+// limits of zero or less cache nothing.
+func TestTextCacheDefaults(t *testing.T) {
+	var (
+		cache  = ui.NewTextCache()
+		engine = softrender.New(100, 40)
+		text   = render.Text{Text: "Hello", Size: 12, Color: testForeground}
+	)
+	cache.DrawText(engine, text, render.NewPoint(10, 10))
+	if stats := cache.Stats(); stats.TextureMisses != 1 || stats.Textures != 1 {
+		t.Errorf("expected the engine to render a texture: %+v", stats)
+	}
+
+	cache.Clear()
+	cache.MaxRects = -1
+	cache.MaxTextureBytes = -1
+	cache.ComputeTextRect(engine, text)
+	cache.DrawText(engine, text, render.NewPoint(10, 10))
+	if stats := cache.Stats(); stats.Rects != 0 || stats.Textures != 0 {
+		t.Errorf("expected nothing to be cached: %+v", stats)
+	}
+}
+
+// Labels use the DefaultTextCache, looking up new text when their variable
+// changes.
+func TestLabelTextCache(t *testing.T) {
+	defer emptyTextCache()()
+	var (
+		engine = softrender.New(100, 100)
+		score  = 1
+		label  = ui.NewLabel(ui.Label{IntVariable: &score})
+	)
+
+	label.Compute(engine)
+	label.Compute(engine)
+	score = 2
+	label.Compute(engine)
+
+	stats := ui.DefaultTextCache.Stats()
+	if stats.RectMisses != 2 || stats.RectHits != 1 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
@@ -11,6 +11,7 @@ var (
 	ButtonHoverColor      = render.RGBA(200, 255, 255, 255)
 	ButtonOutlineColor    = render.Black
 	InputBackgroundColor  = render.White
+	FocusRingColor        = render.RGBA(0, 102, 204, 255)
 
 	BorderColorOffset = 40
 )
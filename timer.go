@@ -0,0 +1,158 @@
+package ui
+
+import (
+	"sort"
+	"time"
+
+	"git.kirsle.net/go/render/event"
+)
+
+/*
+timer.go holds the Supervisor's clock and the timers that it runs from Loop.
+*/
+
+// Timer is a function scheduled to run by Supervisor.After or Supervisor.Every.
+type Timer struct {
+	supervisor *Supervisor
+	when       time.Time     // next time the timer fires
+	interval   time.Duration // repeat interval for Every, or zero
+	fn         func()
+	stopped    bool
+}
+
+// SetClock overrides the Supervisor's clock, which is time.Now by default.
+//
+// Timers, tweens, key repeats and multi-clicks all go by the time at the
+// start of each Loop. A custom clock lets tests and replays control it; pass
+// nil to use the wall clock again.
+func (s *Supervisor) SetClock(clock func() time.Time) {
+	s.clock = clock
+}
+
+// Now returns the current time by the Supervisor's clock.
+func (s *Supervisor) Now() time.Time {
+	if s.clock != nil {
+		return s.clock()
+	}
+	return time.Now()
+}
+
+// LoopTime returns the time at the start of the current (or last) Loop, by
+// the Supervisor's clock. Timers, tweens, key repeats and multi-clicks all go
+// by this time.
+func (s *Supervisor) LoopTime() time.Time {
+	return s.tick
+}
+
+// OnLoop registers a function to be called at the start of each Loop with
+// the event state, e.g. to record the input of a session (see the replay
+// package).
+func (s *Supervisor) OnLoop(fn func(*event.State)) {
+	s.loopHooks = append(s.loopHooks, fn)
+}
+
+// After schedules a function to run once, on the first Loop after the
+// duration has passed.
+func (s *Supervisor) After(d time.Duration, fn func()) *Timer {
+	return s.addTimer(d, 0, fn)
+}
+
+// Every schedules a function to run repeatedly, on the first Loop after each
+// interval has passed, until the Timer is stopped. If Loop falls behind by
+// more than an interval, the missed runs are skipped rather than run in a
+// burst.
+func (s *Supervisor) Every(interval time.Duration, fn func()) *Timer {
+	if interval <= 0 {
+		interval = time.Nanosecond
+	}
+	return s.addTimer(interval, interval, fn)
+}
+
+// addTimer schedules a new timer.
+func (s *Supervisor) addTimer(d, interval time.Duration, fn func()) *Timer {
+	t := &Timer{
+		supervisor: s,
+		when:       s.Now().Add(d),
+		interval:   interval,
+		fn:         fn,
+	}
+
+	s.lock.Lock()
+	s.timers = append(s.timers, t)
+	s.lock.Unlock()
+	return t
+}
+
+// Stop cancels the timer. Returns false if it had already stopped (or a
+// timer from After had already run).
+func (t *Timer) Stop() bool {
+	s := t.supervisor
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if t.stopped {
+		return false
+	}
+	t.stopped = true
+
+	s.removeTimer(t)
+	return true
+}
+
+// removeTimer takes a timer off the list. The caller must hold the lock.
+func (s *Supervisor) removeTimer(t *Timer) {
+	for i, other := range s.timers {
+		if other == t {
+			s.timers = append(s.timers[:i], s.timers[i+1:]...)
+			return
+		}
+	}
+}
+
+// Active returns whether the timer is still scheduled to run.
+func (t *Timer) Active() bool {
+	t.supervisor.lock.RLock()
+	defer t.supervisor.lock.RUnlock()
+	return !t.stopped
+}
+
+// runTimers is a subroutine of Supervisor.Loop that runs the timers that are
+// due, in the order of when they were due. Each timer runs at most once per
+// Loop, and timers added while these run wait for the next Loop.
+func (s *Supervisor) runTimers(now time.Time) {
+	s.lock.RLock()
+	var due = []*Timer{}
+	for _, t := range s.timers {
+		if !t.when.After(now) {
+			due = append(due, t)
+		}
+	}
+	s.lock.RUnlock()
+
+	sort.SliceStable(due, func(i, j int) bool {
+		return due[i].when.Before(due[j].when)
+	})
+
+	for _, t := range due {
+		// An earlier timer may have stopped this one.
+		s.lock.Lock()
+		if t.stopped {
+			s.lock.Unlock()
+			continue
+		}
+		if t.interval > 0 {
+			t.when = t.when.Add(t.interval)
+			if !t.when.After(now) {
+				t.when = now.Add(t.interval)
+			}
+		} else {
+			t.stopped = true
+			s.removeTimer(t)
+		}
+		s.lock.Unlock()
+
+		if t.fn != nil {
+			t.fn()
+		}
+	}
+}
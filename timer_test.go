@@ -0,0 +1,118 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"git.kirsle.net/go/render"
+	"git.kirsle.net/go/render/event"
+)
+
+// fakeClock is a Supervisor clock that only moves when told to.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func TestTimers(t *testing.T) {
+	var (
+		s     = NewSupervisor()
+		clock = &fakeClock{now: time.Unix(0, 0)}
+		ev    = event.NewState()
+		log   = []string{}
+	)
+	s.SetClock(clock.Now)
+
+	s.After(100*time.Millisecond, func() {
+		log = append(log, "after")
+	})
+	every := s.Every(50*time.Millisecond, func() {
+		log = append(log, "every")
+	})
+	cancelled := s.After(10*time.Millisecond, func() {
+		log = append(log, "cancelled")
+	})
+	if !cancelled.Stop() || cancelled.Stop() {
+		t.Errorf("Stop should return true only the first time")
+	}
+
+	step := func(d time.Duration) {
+		clock.now = clock.now.Add(d)
+		s.Loop(ev)
+	}
+
+	step(40 * time.Millisecond)  // nothing due
+	step(10 * time.Millisecond)  // every @50
+	step(50 * time.Millisecond)  // after @100 then every @100, in the order added
+	step(170 * time.Millisecond) // every once, skipping the missed runs
+	every.Stop()
+	step(100 * time.Millisecond)
+
+	expect := []string{"every", "after", "every", "every"}
+	if len(log) != len(expect) {
+		t.Fatalf("expected %v, got %v", expect, log)
+	}
+	for i := range expect {
+		if log[i] != expect[i] {
+			t.Errorf("run %d: expected %s, got %s", i, expect[i], log[i])
+		}
+	}
+	if every.Active() {
+		t.Errorf("stopped timer is still active")
+	}
+}
+
+func TestTweens(t *testing.T) {
+	var (
+		s     = NewSupervisor()
+		clock = &fakeClock{now: time.Unix(0, 0)}
+		ev    = event.NewState()
+		frame = NewFrame("Panel")
+		done  int
+	)
+	s.SetClock(clock.Now)
+	frame.MoveTo(render.NewPoint(0, 0))
+	frame.SetBackground(render.RGBA(0, 0, 0, 255))
+
+	move := s.TweenMove(frame, render.NewPoint(100, 50), time.Second, Linear).OnDone(func() {
+		done++
+	})
+	s.TweenBackground(frame, render.RGBA(200, 100, 0, 255), time.Second, nil)
+
+	clock.now = clock.now.Add(500 * time.Millisecond)
+	s.Loop(ev)
+	if p := frame.Point(); p != render.NewPoint(50, 25) {
+		t.Errorf("expected the frame halfway at 50,25, got %s", p)
+	}
+	if c := frame.Background(); c != render.RGBA(100, 50, 0, 255) {
+		t.Errorf("expected the background halfway, got %+v", c)
+	}
+
+	// A new tween of the same property replaces the old one, without
+	// completing it.
+	slide := s.TweenMove(frame, render.NewPoint(0, 0), time.Second, EaseOut)
+	if move.Running() || !slide.Running() {
+		t.Errorf("expected the new tween to replace the old one")
+	}
+
+	clock.now = clock.now.Add(2 * time.Second)
+	s.Loop(ev)
+	if p := frame.Point(); p != render.NewPoint(0, 0) {
+		t.Errorf("expected the frame back at 0,0, got %s", p)
+	}
+	if c := frame.Background(); c != render.RGBA(200, 100, 0, 255) {
+		t.Errorf("expected the final background, got %+v", c)
+	}
+	if done != 0 || slide.Running() {
+		t.Errorf("expected the replaced tween's OnDone not to run, and the new tween to complete")
+	}
+
+	for _, easing := range []Easing{Linear, EaseIn, EaseOut, EaseInOut} {
+		if easing(0) != 0 || easing(1) != 1 {
+			t.Errorf("easing should map 0 to 0 and 1 to 1")
+		}
+	}
+}
@@ -197,7 +197,7 @@ func (w *Tooltip) computeText(e render.Engine) {
 		}
 
 		text.Text = line // only this line at this time.
-		rect, err := e.ComputeTextRect(text)
+		rect, err := DefaultTextCache.ComputeTextRect(e, text)
 		if err != nil {
 			panic(fmt.Sprintf("%s: failed to compute text rect: %s", w, err)) // TODO return an error
 		}
@@ -244,7 +244,7 @@ func (w *Tooltip) presentText(e render.Engine, P render.Point) {
 	w.DrawBox(e, P)
 	for i, line := range strings.Split(text.Text, "\n") {
 		text.Text = line
-		e.DrawText(text, render.Point{
+		DefaultTextCache.DrawText(e, text, render.Point{
 			X: P.X + padX,
 			Y: P.Y + padY + (i * w.lineHeight),
 		})
@@ -0,0 +1,240 @@
+package ui
+
+import (
+	"math"
+	"time"
+
+	"git.kirsle.net/go/render"
+)
+
+/*
+tween.go holds the tweening of widget properties over time, run by the
+Supervisor's Loop.
+*/
+
+// Easing is a curve that maps the linear progress of a tween, from 0 to 1,
+// to the eased progress. It should return 0 for 0 and 1 for 1.
+type Easing func(t float64) float64
+
+// Easing curves for tweens.
+var (
+	Linear Easing = func(t float64) float64 {
+		return t
+	}
+
+	// EaseIn starts slow and speeds up.
+	EaseIn Easing = func(t float64) float64 {
+		return t * t * t
+	}
+
+	// EaseOut starts fast and slows down to a stop.
+	EaseOut Easing = func(t float64) float64 {
+		t = 1 - t
+		return 1 - t*t*t
+	}
+
+	// EaseInOut starts slow, speeds up and slows down again.
+	EaseInOut Easing = func(t float64) float64 {
+		if t < 0.5 {
+			return 4 * t * t * t
+		}
+		t = -2*t + 2
+		return 1 - t*t*t/2
+	}
+)
+
+// Tween animates a value over time. Create one with Supervisor.Tween, or
+// one of the helpers for widget properties such as Supervisor.TweenMove.
+type Tween struct {
+	supervisor *Supervisor
+	key        tweenKey
+	start      time.Time
+	duration   time.Duration
+	easing     Easing
+	update     func(progress float64)
+	done       []func()
+	running    bool
+}
+
+// tweenKey identifies a widget property being tweened, so that a new tween
+// of the same property replaces the old one.
+type tweenKey struct {
+	widget   Widget
+	property string
+}
+
+// Tween starts a tween that runs for the given duration. On each Loop, the
+// update function is called with the eased progress, from 0 to 1; it's
+// called with exactly 1 on the last Loop, when the tween completes.
+//
+// A nil easing means Linear.
+func (s *Supervisor) Tween(d time.Duration, easing Easing, update func(progress float64)) *Tween {
+	return s.addTween(tweenKey{}, d, easing, update)
+}
+
+// TweenMove animates a widget's position (with MoveTo) to a new point.
+func (s *Supervisor) TweenMove(w Widget, to render.Point, d time.Duration, easing Easing) *Tween {
+	from := w.Point()
+	return s.addTween(tweenKey{w, "point"}, d, easing, func(p float64) {
+		w.MoveTo(render.NewPoint(
+			lerpInt(from.X, to.X, p),
+			lerpInt(from.Y, to.Y, p),
+		))
+	})
+}
+
+// TweenResize animates a widget's size (with Resize) to a new size.
+func (s *Supervisor) TweenResize(w Widget, to render.Rect, d time.Duration, easing Easing) *Tween {
+	from := w.Size()
+	return s.addTween(tweenKey{w, "size"}, d, easing, func(p float64) {
+		w.Resize(render.NewRect(
+			lerpInt(from.W, to.W, p),
+			lerpInt(from.H, to.H, p),
+		))
+	})
+}
+
+// TweenBackground animates a widget's background color to a new color.
+func (s *Supervisor) TweenBackground(w Widget, to render.Color, d time.Duration, easing Easing) *Tween {
+	from := w.Background()
+	return s.addTween(tweenKey{w, "background"}, d, easing, func(p float64) {
+		w.SetBackground(lerpColor(from, to, p))
+	})
+}
+
+// TweenForeground animates a widget's foreground color to a new color.
+func (s *Supervisor) TweenForeground(w Widget, to render.Color, d time.Duration, easing Easing) *Tween {
+	from := w.Foreground()
+	return s.addTween(tweenKey{w, "foreground"}, d, easing, func(p float64) {
+		w.SetForeground(lerpColor(from, to, p))
+	})
+}
+
+// addTween starts a tween, stopping any other tween with the same key.
+func (s *Supervisor) addTween(key tweenKey, d time.Duration, easing Easing, update func(float64)) *Tween {
+	if easing == nil {
+		easing = Linear
+	}
+
+	t := &Tween{
+		supervisor: s,
+		key:        key,
+		start:      s.Now(),
+		duration:   d,
+		easing:     easing,
+		update:     update,
+		running:    true,
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if key.widget != nil {
+		for _, other := range s.tweens {
+			if other.key == key {
+				other.running = false
+			}
+		}
+	}
+	s.tweens = append(s.tweens, t)
+	return t
+}
+
+// OnDone adds a function to call when the tween completes. It is not called
+// if the tween is stopped. Returns the tween, for chaining.
+func (t *Tween) OnDone(fn func()) *Tween {
+	t.done = append(t.done, fn)
+	return t
+}
+
+// Running returns whether the tween has yet to complete or be stopped.
+func (t *Tween) Running() bool {
+	t.supervisor.lock.RLock()
+	defer t.supervisor.lock.RUnlock()
+	return t.running
+}
+
+// Stop the tween where it is, without completing it. Returns false if it
+// wasn't running.
+func (t *Tween) Stop() bool {
+	t.supervisor.lock.Lock()
+	defer t.supervisor.lock.Unlock()
+
+	if !t.running {
+		return false
+	}
+	t.running = false
+	return true
+}
+
+// Finish jumps the tween to its end and completes it now.
+func (t *Tween) Finish() {
+	if t.Stop() {
+		t.complete()
+	}
+}
+
+// complete sets the final value and calls the OnDone functions.
+func (t *Tween) complete() {
+	if t.update != nil {
+		t.update(1)
+	}
+	for _, fn := range t.done {
+		fn()
+	}
+}
+
+// runTweens is a subroutine of Supervisor.Loop that advances the tweens.
+func (s *Supervisor) runTweens(now time.Time) {
+	s.lock.Lock()
+	var (
+		tweens  = s.tweens
+		running = make([]*Tween, 0, len(s.tweens))
+	)
+	for _, t := range tweens {
+		if t.running {
+			running = append(running, t)
+		}
+	}
+	s.tweens = running
+	s.lock.Unlock()
+
+	for _, t := range running {
+		var progress = 1.0
+		if t.duration > 0 {
+			progress = float64(now.Sub(t.start)) / float64(t.duration)
+		}
+
+		if progress >= 1 {
+			t.Finish()
+			continue
+		}
+		if t.update != nil && t.Running() {
+			t.update(t.easing(math.Max(progress, 0)))
+		}
+	}
+}
+
+// lerpInt interpolates between two integers.
+func lerpInt(a, b int, t float64) int {
+	return a + int(math.Round(float64(b-a)*t))
+}
+
+// lerpColor interpolates between two colors, including their alpha.
+func lerpColor(a, b render.Color, t float64) render.Color {
+	channel := func(a, b uint8) uint8 {
+		v := lerpInt(int(a), int(b), t)
+		if v < 0 {
+			v = 0
+		} else if v > 255 {
+			v = 255
+		}
+		return uint8(v)
+	}
+	return render.RGBA(
+		channel(a.Red, b.Red),
+		channel(a.Green, b.Green),
+		channel(a.Blue, b.Blue),
+		channel(a.Alpha, b.Alpha),
+	)
+}
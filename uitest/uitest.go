@@ -0,0 +1,327 @@
+// Package uitest drives a ui.Supervisor with scripted input for end-to-end
+// tests of a user interface.
+//
+// A Driver moves the mouse to widgets (found by their ID) and clicks them,
+// drags, types text and presses key combinations, running the Supervisor's
+// Loop for each step on a headless softrender engine. Time is simulated:
+// each tick advances the Supervisor's clock by FrameTime.
+//
+//	d := uitest.New(t, supervisor, window)
+//	d.Click("Button<OK>")
+//	d.AssertHidden("Window<Settings false>")
+package uitest
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"git.kirsle.net/go/render"
+	"git.kirsle.net/go/render/event"
+	"git.kirsle.net/go/ui"
+	"git.kirsle.net/go/ui/softrender"
+)
+
+// Default settings for new Drivers.
+var (
+	DefaultWidth     = 640
+	DefaultHeight    = 480
+	DefaultFrameTime = 16 * time.Millisecond
+)
+
+// Driver feeds scripted input to a Supervisor.
+type Driver struct {
+	T          testing.TB
+	Supervisor *ui.Supervisor
+	Root       ui.Widget // computed and presented each tick (may be nil)
+	Engine     *softrender.Engine
+
+	// FrameTime is how far the clock moves on each tick.
+	FrameTime time.Duration
+
+	// DragSteps is the number of ticks a Drag takes to move between the
+	// two points.
+	DragSteps int
+
+	state *event.State
+	now   time.Time
+	ticks int
+}
+
+// New creates a Driver for a Supervisor. The root widget, if not nil, is
+// computed and presented on each tick, as a main loop would, and is searched
+// along with the supervised widgets when finding widgets by ID.
+//
+// The Supervisor's clock is taken over by the Driver.
+func New(t testing.TB, s *ui.Supervisor, root ui.Widget) *Driver {
+	d := &Driver{
+		T:          t,
+		Supervisor: s,
+		Root:       root,
+		Engine:     softrender.New(DefaultWidth, DefaultHeight),
+		FrameTime:  DefaultFrameTime,
+		DragSteps:  4,
+		state:      event.NewState(),
+		now:        time.Unix(0, 0),
+	}
+	s.SetClock(d.Now)
+	return d
+}
+
+// Now returns the simulated time.
+func (d *Driver) Now() time.Time {
+	return d.now
+}
+
+// Ticks returns the number of ticks run so far.
+func (d *Driver) Ticks() int {
+	return d.ticks
+}
+
+// State returns the event state sent on each tick, to set up input that the
+// Driver has no method for.
+func (d *Driver) State() *event.State {
+	return d.state
+}
+
+// Tick runs n frames: the clock moves forward by FrameTime, the root widget
+// is computed, the Supervisor's Loop runs with the current input, and the
+// root widget and the Supervisor are presented.
+func (d *Driver) Tick(n int) {
+	for i := 0; i < n; i++ {
+		d.now = d.now.Add(d.FrameTime)
+		d.ticks++
+
+		d.layout()
+		d.Supervisor.Loop(d.state)
+		d.Supervisor.Compute(d.Engine)
+
+		if d.Root != nil {
+			d.Root.Present(d.Engine, d.Root.Point())
+		}
+		d.Supervisor.Present(d.Engine)
+		d.Engine.Present()
+	}
+}
+
+// Wait runs as many ticks as it takes for the clock to move forward by the
+// duration, e.g. to let timers and tweens run.
+func (d *Driver) Wait(duration time.Duration) {
+	var n = int((duration + d.FrameTime - 1) / d.FrameTime)
+	d.Tick(n)
+}
+
+// Find a widget by its ID. Fails the test if there is no such widget.
+func (d *Driver) Find(id string) ui.Widget {
+	d.T.Helper()
+
+	if w := d.find(id); w != nil {
+		return w
+	}
+	d.T.Fatalf("uitest: no widget with ID %q", id)
+	return nil
+}
+
+// find searches the root widget and the supervised widgets for an ID.
+func (d *Driver) find(id string) ui.Widget {
+	if d.Root != nil {
+		if w := ui.FindByID(d.Root, id); w != nil {
+			return w
+		}
+	}
+	for slot := range d.Supervisor.Widgets() {
+		if w := ui.FindByID(slot.Widget(), id); w != nil {
+			return w
+		}
+	}
+	return nil
+}
+
+// Center returns the point on screen at the middle of a widget.
+func Center(w ui.Widget) render.Point {
+	rect := ui.AbsoluteRect(w)
+	return render.NewPoint(rect.X+rect.W/2, rect.Y+rect.H/2)
+}
+
+// MoveTo moves the mouse to the middle of a widget and runs a tick.
+//
+// The root widget is computed first, so a widget that moved because of the
+// last event (e.g. a sibling was hidden) is found where it will be drawn.
+func (d *Driver) MoveTo(id string) {
+	d.T.Helper()
+	d.layout()
+	d.MoveToPoint(Center(d.Find(id)))
+}
+
+// layout computes the root widget.
+func (d *Driver) layout() {
+	if d.Root != nil {
+		d.Root.Compute(d.Engine)
+	}
+}
+
+// MoveToPoint moves the mouse to a point and runs a tick.
+func (d *Driver) MoveToPoint(p render.Point) {
+	d.state.CursorX, d.state.CursorY = p.X, p.Y
+	d.Tick(1)
+}
+
+// Click moves the mouse to a widget and clicks it with the left button.
+//
+// Clicks come quickly after one another in simulated time, so clicking the
+// same widget twice in a row is a double click (see ui.DoubleClickInterval);
+// Wait in between if that's not what the test wants.
+func (d *Driver) Click(id string) {
+	d.T.Helper()
+	d.MoveTo(id)
+	d.press(&d.state.Button1)
+}
+
+// RightClick moves the mouse to a widget and clicks the right button.
+func (d *Driver) RightClick(id string) {
+	d.T.Helper()
+	d.MoveTo(id)
+	d.press(&d.state.Button2)
+}
+
+// DoubleClick moves the mouse to a widget and clicks it twice.
+func (d *Driver) DoubleClick(id string) {
+	d.T.Helper()
+	d.MoveTo(id)
+	d.press(&d.state.Button1)
+	d.press(&d.state.Button1)
+}
+
+// ClickPoint clicks the left mouse button at a point.
+func (d *Driver) ClickPoint(p render.Point) {
+	d.MoveToPoint(p)
+	d.press(&d.state.Button1)
+}
+
+// press and release a mouse button, a tick each.
+func (d *Driver) press(button *bool) {
+	*button = true
+	d.Tick(1)
+	*button = false
+	d.Tick(1)
+}
+
+// Drag presses the left mouse button on one widget, moves to the middle of
+// another and releases it there.
+func (d *Driver) Drag(fromID, toID string) {
+	d.T.Helper()
+	d.layout()
+	d.DragPoints(Center(d.Find(fromID)), Center(d.Find(toID)))
+}
+
+// DragPoints presses the left mouse button at one point, moves to another
+// in DragSteps ticks and releases it there.
+func (d *Driver) DragPoints(from, to render.Point) {
+	d.MoveToPoint(from)
+	d.state.Button1 = true
+	d.Tick(1)
+
+	var steps = d.DragSteps
+	if steps < 1 {
+		steps = 1
+	}
+	for i := 1; i <= steps; i++ {
+		d.MoveToPoint(render.NewPoint(
+			from.X+(to.X-from.X)*i/steps,
+			from.Y+(to.Y-from.Y)*i/steps,
+		))
+	}
+
+	d.state.Button1 = false
+	d.Tick(1)
+}
+
+// Type types text into the focused widget, a key press and release for each
+// character (with Shift for capitals and symbols).
+func (d *Driver) Type(text string) {
+	d.T.Helper()
+	for _, r := range text {
+		key, shift := ui.RuneKey(r)
+		if key == "" {
+			d.T.Fatalf("uitest: no key types %q", r)
+		}
+		d.tap(ui.Accelerator{
+			Key:   key,
+			Shift: shift,
+		})
+	}
+}
+
+// Press presses a key combination like "Tab", "Escape" or "Ctrl-Shift-Z"
+// (see ui.ParseAccelerator).
+func (d *Driver) Press(keys string) {
+	d.T.Helper()
+	accel, err := ui.ParseAccelerator(keys)
+	if err != nil {
+		d.T.Fatalf("uitest: %s", err)
+	}
+	d.tap(accel)
+}
+
+// tap holds down the keys for a tick and releases them for a tick.
+func (d *Driver) tap(accel ui.Accelerator) {
+	d.state.Shift, d.state.Ctrl, d.state.Alt = accel.Shift, accel.Ctrl, accel.Alt
+	d.state.SetKeyDown(accel.Key, true)
+	d.Tick(1)
+
+	d.state.SetKeyDown(accel.Key, false)
+	d.state.Shift, d.state.Ctrl, d.state.Alt = false, false, false
+	d.Tick(1)
+}
+
+// AssertHidden fails the test if the widget is visible.
+func (d *Driver) AssertHidden(id string) {
+	d.T.Helper()
+	if !d.Find(id).Hidden() {
+		d.T.Errorf("uitest: expected %s to be hidden", id)
+	}
+}
+
+// AssertVisible fails the test if the widget is hidden.
+func (d *Driver) AssertVisible(id string) {
+	d.T.Helper()
+	if d.Find(id).Hidden() {
+		d.T.Errorf("uitest: expected %s to be visible", id)
+	}
+}
+
+// AssertFocused fails the test if the widget doesn't hold the keyboard
+// focus.
+func (d *Driver) AssertFocused(id string) {
+	d.T.Helper()
+	if w := d.Find(id); !d.Supervisor.IsFocused(w) {
+		d.T.Errorf("uitest: expected %s to be focused, but %v is", id, d.Supervisor.FocusedWidget())
+	}
+}
+
+// AssertText fails the test if the text of a widget with a Value() string
+// method (such as a Label or Tooltip) is not the expected text.
+func (d *Driver) AssertText(id, want string) {
+	d.T.Helper()
+	w, ok := d.Find(id).(interface{ Value() string })
+	if !ok {
+		d.T.Fatalf("uitest: %s has no text value", id)
+	}
+	if got := w.Value(); got != want {
+		d.T.Errorf("uitest: expected %s to have text %q, got %q", id, want, got)
+	}
+}
+
+// AssertValue fails the test if the variable that a pointer points to, such
+// as the bound variable of a CheckBox or Label, doesn't equal the expected
+// value.
+func (d *Driver) AssertValue(ptr interface{}, want interface{}) {
+	d.T.Helper()
+	v := reflect.ValueOf(ptr)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		d.T.Fatalf("uitest: AssertValue needs a pointer, got %T", ptr)
+	}
+	if got := v.Elem().Interface(); !reflect.DeepEqual(got, want) {
+		d.T.Errorf("uitest: expected the value %v, got %v", want, got)
+	}
+}
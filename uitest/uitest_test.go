@@ -0,0 +1,99 @@
+package uitest
+
+import (
+	"testing"
+
+	"git.kirsle.net/go/render"
+	"git.kirsle.net/go/ui"
+)
+
+func TestDriver(t *testing.T) {
+	var (
+		s      = ui.NewSupervisor()
+		root   = ui.NewFrame("Root")
+		sound  bool
+		typed  string
+		saved  int
+		clicks int
+	)
+	root.Resize(render.NewRect(DefaultWidth, DefaultHeight))
+
+	// A button that hides a panel.
+	panel := ui.NewFrame("Panel")
+	panel.Resize(render.NewRect(100, 40))
+	button := ui.NewButton("Close", ui.NewLabel(ui.Label{Text: "Close"}))
+	button.Handle(ui.Click, func(ed ui.EventData) error {
+		clicks++
+		panel.Hide()
+		return nil
+	})
+	s.Add(button)
+	root.Pack(button, ui.Pack{Side: ui.N})
+	root.Pack(panel, ui.Pack{Side: ui.N})
+
+	// A checkbox bound to a variable.
+	check := ui.NewCheckbox("Sound", &sound, ui.NewLabel(ui.Label{Text: "Enable sound"}))
+	check.Supervise(s)
+	root.Pack(check, ui.Pack{Side: ui.N})
+
+	// A focusable field that collects typed text into its label.
+	field := ui.NewLabel(ui.Label{TextVariable: &typed})
+	field.Resize(render.NewRect(100, 20))
+	field.SetFocusable(true)
+	field.Handle(ui.KeyPress, func(ed ui.EventData) error {
+		if ed.Rune != 0 {
+			typed += string(ed.Rune)
+		}
+		return nil
+	})
+	s.Add(field)
+	root.Pack(field, ui.Pack{Side: ui.N})
+
+	s.AddShortcut("Ctrl-S", nil, func() {
+		saved++
+	})
+
+	d := New(t, s, root)
+	d.Tick(1)
+
+	d.AssertVisible("Frame<Panel>")
+	d.Click("Button<Close>")
+	d.AssertHidden("Frame<Panel>")
+	if clicks != 1 {
+		t.Errorf("expected 1 click, got %d", clicks)
+	}
+
+	d.Click(`Label<"Enable sound">`)
+	d.AssertValue(&sound, true)
+
+	d.Click(`Label<"">`)
+	d.AssertFocused(`Label<"">`)
+	d.Type("Hi!")
+	d.AssertText(`Label<"Hi!">`, "Hi!")
+
+	d.Press("Ctrl-S")
+	if saved != 1 {
+		t.Errorf("expected the shortcut to run once, got %d", saved)
+	}
+}
+
+func TestDriverDrag(t *testing.T) {
+	var (
+		s      = ui.NewSupervisor()
+		window = ui.NewWindow("Drag Me")
+	)
+	window.Configure(ui.Config{
+		Width:  200,
+		Height: 100,
+	})
+	window.MoveTo(render.NewPoint(10, 10))
+	window.Supervise(s)
+
+	d := New(t, s, nil)
+	window.Compute(d.Engine)
+	d.DragPoints(render.NewPoint(50, 15), render.NewPoint(150, 65))
+
+	if p := window.Point(); p != render.NewPoint(110, 60) {
+		t.Errorf("expected the window dragged to 110,60, got %d,%d", p.X, p.Y)
+	}
+}
@@ -0,0 +1,37 @@
+package ui
+
+import (
+	"git.kirsle.net/go/render"
+	"git.kirsle.net/go/render/event"
+)
+
+/*
+wheel.go holds the Supervisor methods that route the mouse wheel to the
+Scroll event of the widget under the cursor.
+*/
+
+// loopWheel is a subroutine of Supervisor.Loop that sends mouse wheel
+// movement as a Scroll event, with the WheelX and WheelY of the EventData
+// set.
+//
+// The event is dispatched to the deepest widget under the cursor and bubbles
+// up through its parents (see Dispatch), so a container can scroll with a
+// HandleBubble(Scroll) handler that stops the propagation once it scrolled.
+// Returns true if a handler stopped the event.
+func (s *Supervisor) loopWheel(ev *event.State, XY render.Point, hovering []WidgetSlot) bool {
+	if ev.WheelX == 0 && ev.WheelY == 0 {
+		return false
+	}
+
+	var target = s.hitTest(XY, hovering)
+	if target == nil {
+		return false
+	}
+
+	stopped, _ := s.propagate(target, Scroll, EventData{
+		Point:  XY,
+		WheelX: ev.WheelX,
+		WheelY: ev.WheelY,
+	}, true)
+	return stopped
+}
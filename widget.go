@@ -1,6 +1,8 @@
 package ui
 
 import (
+	"sync/atomic"
+
 	"git.kirsle.net/go/render"
 	"git.kirsle.net/go/ui/theme"
 )
@@ -121,8 +123,40 @@ type BaseWidget struct {
 	outlineColor render.Color
 	outlineSize  int
 	handlers     map[Event][]func(EventData) error
+	capture      map[Event][]func(EventData) error
+	bubble       map[Event][]func(EventData) error
 	hasParent    bool
 	parent       Widget
+	focusable    bool
+	hasFocus     bool
+	clean        bool      // drawn and unchanged since, see Invalidate
+	drawn        drawState // where it was last presented, see Supervisor.Damage
+	geometry     uint64    // changes to its tree's geometry, see geometryChanged
+}
+
+// geometer is a widget that counts the changes to the geometry of its tree.
+type geometer interface {
+	geometryCounter() *uint64
+}
+
+// geometryCounter returns the widget's count of geometry changes. It is only
+// accessed atomically.
+func (w *BaseWidget) geometryCounter() *uint64 {
+	return &w.geometry
+}
+
+// geometryChanged is called when a widget has moved, resized or changed its
+// parent. The change is counted on the widget and all its ancestors, so the
+// root of a tree knows when any widget in it moved; a Supervisor compares the
+// counts of the trees it supervises to know when its cached hit-test rects
+// are stale.
+func (w *BaseWidget) geometryChanged() {
+	atomic.AddUint64(&w.geometry, 1)
+	for parent, ok := w.Parent(); ok; parent, ok = parent.Parent() {
+		if g, ok := parent.(geometer); ok {
+			atomic.AddUint64(g.geometryCounter(), 1)
+		}
+	}
 }
 
 // SetID sets a string name for your widget, helpful for debugging purposes.
@@ -160,6 +194,7 @@ func (w *BaseWidget) Configure(c Config) {
 		if c.Height != 0 {
 			w.height = c.Height
 		}
+		w.geometryChanged()
 	}
 
 	if c.Margin != 0 {
@@ -180,6 +215,7 @@ func (w *BaseWidget) Configure(c Config) {
 
 	if c.BorderSize != 0 {
 		w.borderSize = c.BorderSize
+		w.geometryChanged()
 	}
 	if c.BorderStyle != BorderNone {
 		w.borderStyle = c.BorderStyle
@@ -187,6 +223,10 @@ func (w *BaseWidget) Configure(c Config) {
 	if c.OutlineSize != 0 {
 		w.outlineSize = c.OutlineSize
 	}
+
+	if c != (Config{}) {
+		w.Invalidate()
+	}
 }
 
 // Rect returns the widget's absolute rectangle, the combined Size and Point.
@@ -206,13 +246,21 @@ func (w *BaseWidget) Point() render.Point {
 
 // MoveTo updates the X,Y position to the new point.
 func (w *BaseWidget) MoveTo(v render.Point) {
-	w.point = v
+	if w.point != v {
+		w.point = v
+		w.geometryChanged()
+		w.invalidateMove()
+	}
 }
 
 // MoveBy adds the X,Y values to the widget's current position.
 func (w *BaseWidget) MoveBy(v render.Point) {
-	w.point.X += v.X
-	w.point.Y += v.Y
+	if v.X != 0 || v.Y != 0 {
+		w.point.X += v.X
+		w.point.Y += v.Y
+		w.geometryChanged()
+		w.invalidateMove()
+	}
 }
 
 // Size returns the box with W and H attributes containing the size of the
@@ -242,21 +290,28 @@ func (w *BaseWidget) FixedSize() bool {
 // Resize sets the size of the widget to the .W and .H attributes of a rect.
 func (w *BaseWidget) Resize(v render.Rect) {
 	w.fixedSize = true
-	w.width = v.W
-	w.height = v.H
+	w.ResizeAuto(v)
 }
 
 // ResizeBy resizes by a relative amount.
 func (w *BaseWidget) ResizeBy(v render.Rect) {
 	w.fixedSize = true
-	w.width += v.W
-	w.height += v.H
+	if v.W != 0 || v.H != 0 {
+		w.width += v.W
+		w.height += v.H
+		w.geometryChanged()
+		w.Invalidate()
+	}
 }
 
 // ResizeAuto sets the size of the widget but doesn't set the fixedSize flag.
 func (w *BaseWidget) ResizeAuto(v render.Rect) {
-	w.width = v.W
-	w.height = v.H
+	if w.width != v.W || w.height != v.H {
+		w.width = v.W
+		w.height = v.H
+		w.geometryChanged()
+		w.Invalidate()
+	}
 }
 
 // BoxThickness returns the full sum of the padding, border and outline.
@@ -279,6 +334,7 @@ func (w *BaseWidget) Parent() (Widget, bool) {
 // widgets like Frame when they add a child widget to their care.
 // Pass a nil parent to unset the parent.
 func (w *BaseWidget) SetParent(parent Widget) {
+	w.geometryChanged() // counted by the old tree and the new one
 	if parent == nil {
 		w.hasParent = false
 		w.parent = nil
@@ -286,6 +342,8 @@ func (w *BaseWidget) SetParent(parent Widget) {
 		w.hasParent = true
 		w.parent = parent
 	}
+	w.geometryChanged()
+	w.Invalidate()
 }
 
 // Children returns the widget's children, to be implemented by containers.
@@ -296,12 +354,18 @@ func (w *BaseWidget) Children() []Widget {
 
 // Hide the widget from being rendered.
 func (w *BaseWidget) Hide() {
-	w.hidden = true
+	if !w.hidden {
+		w.hidden = true
+		w.Invalidate()
+	}
 }
 
 // Show the widget.
 func (w *BaseWidget) Show() {
-	w.hidden = false
+	if w.hidden {
+		w.hidden = false
+		w.Invalidate()
+	}
 }
 
 // Hidden returns whether the widget is hidden. If this widget is not hidden,
@@ -324,6 +388,31 @@ func (w *BaseWidget) Hidden() bool {
 	return false
 }
 
+// Focusable returns whether the widget can receive the keyboard focus.
+func (w *BaseWidget) Focusable() bool {
+	return w.focusable
+}
+
+// SetFocusable sets whether the widget can receive the keyboard focus.
+func (w *BaseWidget) SetFocusable(v bool) {
+	w.focusable = v
+}
+
+// HasFocus returns whether the widget currently has the keyboard focus.
+func (w *BaseWidget) HasFocus() bool {
+	return w.hasFocus
+}
+
+// SetHasFocus sets the widget's keyboard focus state. Note: if you're using
+// the Supervisor, do NOT call this method -- keyboard focus is managed by
+// the Supervisor, see Supervisor.Focus().
+func (w *BaseWidget) SetHasFocus(v bool) {
+	if w.hasFocus != v {
+		w.hasFocus = v
+		w.Invalidate()
+	}
+}
+
 // DrawBox draws the border and outline.
 func (w *BaseWidget) DrawBox(e render.Engine, P render.Point) {
 	var (
@@ -394,6 +483,16 @@ func (w *BaseWidget) DrawBox(e render.Engine, P render.Point) {
 	if w.Background() != render.Invisible {
 		e.DrawBox(w.Background(), box)
 	}
+
+	// Focus ring around the full size of the widget.
+	if w.hasFocus {
+		e.DrawRect(theme.FocusRingColor, render.Rect{
+			X: P.X,
+			Y: P.Y,
+			W: S.W,
+			H: S.H,
+		})
+	}
 }
 
 // Margin returns the margin width.
@@ -403,7 +502,10 @@ func (w *BaseWidget) Margin() int {
 
 // SetMargin sets the margin width.
 func (w *BaseWidget) SetMargin(v int) {
-	w.margin = v
+	if w.margin != v {
+		w.margin = v
+		w.Invalidate()
+	}
 }
 
 // Background returns the background color.
@@ -413,7 +515,10 @@ func (w *BaseWidget) Background() render.Color {
 
 // SetBackground sets the color.
 func (w *BaseWidget) SetBackground(c render.Color) {
-	w.background = c
+	if w.background != c {
+		w.background = c
+		w.Invalidate()
+	}
 }
 
 // Foreground returns the foreground color.
@@ -423,7 +528,10 @@ func (w *BaseWidget) Foreground() render.Color {
 
 // SetForeground sets the color.
 func (w *BaseWidget) SetForeground(c render.Color) {
-	w.foreground = c
+	if w.foreground != c {
+		w.foreground = c
+		w.Invalidate()
+	}
 }
 
 // BorderStyle returns the border style.
@@ -433,7 +541,10 @@ func (w *BaseWidget) BorderStyle() BorderStyle {
 
 // SetBorderStyle sets the border style.
 func (w *BaseWidget) SetBorderStyle(v BorderStyle) {
-	w.borderStyle = v
+	if w.borderStyle != v {
+		w.borderStyle = v
+		w.Invalidate()
+	}
 }
 
 // BorderColor returns the border color, or defaults to the background color.
@@ -446,7 +557,10 @@ func (w *BaseWidget) BorderColor() render.Color {
 
 // SetBorderColor sets the border color.
 func (w *BaseWidget) SetBorderColor(c render.Color) {
-	w.borderColor = c
+	if w.borderColor != c {
+		w.borderColor = c
+		w.Invalidate()
+	}
 }
 
 // BorderSize returns the border thickness.
@@ -456,7 +570,11 @@ func (w *BaseWidget) BorderSize() int {
 
 // SetBorderSize sets the border thickness.
 func (w *BaseWidget) SetBorderSize(v int) {
-	w.borderSize = v
+	if w.borderSize != v {
+		w.borderSize = v
+		w.geometryChanged()
+		w.Invalidate()
+	}
 }
 
 // OutlineColor returns the background color.
@@ -466,7 +584,10 @@ func (w *BaseWidget) OutlineColor() render.Color {
 
 // SetOutlineColor sets the color.
 func (w *BaseWidget) SetOutlineColor(c render.Color) {
-	w.outlineColor = c
+	if w.outlineColor != c {
+		w.outlineColor = c
+		w.Invalidate()
+	}
 }
 
 // OutlineSize returns the outline thickness.
@@ -476,7 +597,10 @@ func (w *BaseWidget) OutlineSize() int {
 
 // SetOutlineSize sets the outline thickness.
 func (w *BaseWidget) SetOutlineSize(v int) {
-	w.outlineSize = v
+	if w.outlineSize != v {
+		w.outlineSize = v
+		w.Invalidate()
+	}
 }
 
 // Compute calls the base widget's Compute function, which just triggers
@@ -488,8 +612,10 @@ func (w *BaseWidget) Compute(e render.Engine) {
 }
 
 // Present calls the base widget's Present function, which just triggers
-// events on widgets that want to be notified when the widget presents.
+// events on widgets that want to be notified when the widget presents, and
+// remembers where the widget was drawn.
 func (w *BaseWidget) Present(e render.Engine, p render.Point) {
+	w.drawn.presented(w, p)
 	w.Event(Present, EventData{
 		Point:  p,
 		Engine: e,
@@ -511,6 +637,11 @@ func (w *BaseWidget) Event(event Event, e EventData) error {
 	return ErrNoEventHandler
 }
 
+// hasHandler returns whether the widget has any handlers for an event.
+func (w *BaseWidget) hasHandler(event Event) bool {
+	return len(w.handlers[event]) > 0
+}
+
 // Handle an event in the widget.
 func (w *BaseWidget) Handle(event Event, fn func(EventData) error) {
 	if w.handlers == nil {
@@ -524,6 +655,45 @@ func (w *BaseWidget) Handle(event Event, fn func(EventData) error) {
 	w.handlers[event] = append(w.handlers[event], fn)
 }
 
+// HandleCapture handles an event in the capture phase: the handler is called
+// for events dispatched to this widget or any of its descendants, before the
+// target widget's own handlers. See Supervisor.Dispatch.
+func (w *BaseWidget) HandleCapture(event Event, fn func(EventData) error) {
+	if w.capture == nil {
+		w.capture = map[Event][]func(EventData) error{}
+	}
+	w.capture[event] = append(w.capture[event], fn)
+}
+
+// HandleBubble handles an event in the bubble phase: the handler is called
+// for events dispatched to this widget or any of its descendants, after the
+// target widget's own handlers. See Supervisor.Dispatch.
+func (w *BaseWidget) HandleBubble(event Event, fn func(EventData) error) {
+	if w.bubble == nil {
+		w.bubble = map[Event][]func(EventData) error{}
+	}
+	w.bubble[event] = append(w.bubble[event], fn)
+}
+
+// phaseEvent calls the capture or bubble handlers for an event. It stops
+// early if a handler stops the propagation.
+func (w *BaseWidget) phaseEvent(phase EventPhase, event Event, e EventData) error {
+	var handlers = w.capture[event]
+	if phase == BubblePhase {
+		handlers = w.bubble[event]
+	}
+
+	for _, fn := range handlers {
+		if res := fn(e); res == ErrStopPropagation {
+			return res
+		}
+		if e.propagation != nil && e.propagation.stopped {
+			return ErrStopPropagation
+		}
+	}
+	return nil
+}
+
 // OnMouseOut should be overridden on widgets who want this event.
 func (w *BaseWidget) OnMouseOut(render.Point) {}
 
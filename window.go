@@ -227,6 +227,15 @@ func (w *Window) Supervise(s *Supervisor) {
 		return nil
 	})
 
+	// Double-clicking the title bar toggles maximized, if the window has
+	// a maximize button.
+	w.titleBar.Handle(DoubleClick, func(ed EventData) error {
+		if !w.titleButtons[1].Hidden() {
+			w.Event(MaximizeWindow, ed)
+		}
+		return nil
+	})
+
 	// Clicking anywhere in the window focuses the window.
 	w.Handle(MouseDown, func(ed EventData) error {
 		s.FocusWindow(w)
@@ -358,6 +367,31 @@ func (w *Window) Place(child Widget, config Place) {
 	w.body.Place(child, config)
 }
 
+// Grid a child widget into the window's main frame.
+func (w *Window) Grid(child Widget, config Grid) {
+	w.content.Grid(child, config)
+}
+
+// GridRow configures a row of the grid of the window's main frame.
+func (w *Window) GridRow(row int, config GridTrack) {
+	w.content.GridRow(row, config)
+}
+
+// GridColumn configures a column of the grid of the window's main frame.
+func (w *Window) GridColumn(column int, config GridTrack) {
+	w.content.GridColumn(column, config)
+}
+
+// Flex a child widget into the window's main frame.
+func (w *Window) Flex(child Widget, config Flex) {
+	w.content.Flex(child, config)
+}
+
+// SetFlexLayout sets the flex layout of the window's main frame.
+func (w *Window) SetFlexLayout(v FlexLayout) {
+	w.content.SetFlexLayout(v)
+}
+
 // TitleBar returns the title bar widgets.
 func (w *Window) TitleBar() (*Frame, *Label) {
 	return w.titleBar, w.titleLabel
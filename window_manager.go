@@ -79,6 +79,41 @@ func (s *Supervisor) addWindow(win *Window) {
 		oldTop.window.SetFocus(false)
 		win.SetFocus(true)
 	}
+	s.invalidate()
+}
+
+// removeWindow takes a Window out of the supervisor's Window Manager. If it
+// was the focused window, the next window in line becomes focused.
+func (s *Supervisor) removeWindow(win *Window) {
+	var node = s.winFocus
+	for node != nil {
+		if node.window != win {
+			node = node.next
+			continue
+		}
+
+		// Bridge the gap in the linked list.
+		if node.prev != nil {
+			node.prev.next = node.next
+		} else {
+			s.winFocus = node.next
+		}
+		if node.next != nil {
+			node.next.prev = node.prev
+		} else {
+			s.winBottom = node.prev
+		}
+
+		win.managed = false
+		s.invalidate()
+		if win.focused {
+			win.SetFocus(false)
+			if s.winFocus != nil {
+				s.winFocus.window.SetFocus(true)
+			}
+		}
+		return
+	}
 }
 
 // FocusWindow brings the given window to the top of the supervisor's focus.
@@ -144,6 +179,7 @@ func (s *Supervisor) FocusWindow(win *Window) error {
 		// Toggle the focus states.
 		oldTop.window.SetFocus(false)
 		target.window.SetFocus(true)
+		s.invalidate()
 	}
 
 	return nil